@@ -2,15 +2,19 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 
 	nnet "github.com/divijg19/Nightshade/internal/net"
 	"github.com/divijg19/Nightshade/internal/persist"
+	"github.com/divijg19/Nightshade/internal/runtime"
 )
 
 type helloMsg struct {
@@ -30,16 +34,141 @@ func defaultSocket() string {
     return filepath.Join(persist.BaseDir(), "socket")
 }
 
+type frameMsg struct {
+    Type string `json:"type"`
+    Frame runtime.Frame `json:"frame"`
+    Camera runtime.Camera `json:"camera"`
+}
+
+// renderFrame draws a w x h window of the stage centred on the camera. The
+// camera subject is '@', other entities '&' ('%' while concealed).
+func renderFrame(m frameMsg, w, h int) string {
+    f := m.Frame
+    ents := map[[2]int]rune{}
+    for _, e := range f.Entities {
+        g := '&'
+        if e.Hidden {
+            g = '%'
+        }
+        if e.ID == m.Camera.Subject {
+            g = '@'
+        }
+        ents[[2]int{e.Position.X, e.Position.Y}] = g
+    }
+    x0 := clamp(m.Camera.Position.X-w/2, 0, f.Width-w)
+    y0 := clamp(m.Camera.Position.Y-h/2, 0, f.Height-h)
+    var b strings.Builder
+    fmt.Fprintf(&b, "Tick %d [%s] camera: %.8s (%s)\n", f.Tick, f.State, m.Camera.Subject, m.Camera.Reason)
+    for y := y0; y < y0+h && y < f.Height; y++ {
+        row := []rune(f.Rows[y])
+        for x := x0; x < x0+w && x < f.Width; x++ {
+            if g, ok := ents[[2]int{x, y}]; ok {
+                b.WriteRune(g)
+            } else {
+                b.WriteRune(row[x])
+            }
+        }
+        b.WriteByte('\n')
+    }
+    for _, d := range f.Departed {
+        fmt.Fprintf(&b, "%.8s %s at (%d,%d)\n", d.ID, d.Outcome, d.Position.X, d.Position.Y)
+    }
+    return b.String()
+}
+
+func clamp(v, lo, hi int) int {
+    if v > hi {
+        v = hi
+    }
+    if v < lo {
+        v = lo
+    }
+    return v
+}
+
+// spectate prints every frame the server broadcasts until it hangs up.
+func spectate(conn net.Conn, w, h int) {
+    if err := nnet.WriteFrame(conn, helloMsg{Type: "spectate"}); err != nil {
+        log.Fatalf("hello write: %v", err)
+    }
+    for {
+        var m frameMsg
+        if err := nnet.ReadFrame(conn, &m); err != nil {
+            return
+        }
+        if m.Type == "frame" {
+            fmt.Print(renderFrame(m, w, h))
+        }
+    }
+}
+
+func defaultAdminSocket() string {
+    if s := os.Getenv("NIGHTSHADE_ADMIN_SOCKET"); s != "" {
+        return s
+    }
+    return filepath.Join(persist.BaseDir(), "admin.socket")
+}
+
+// adminCommand sends one admin command (for example "kick <id>" or
+// "event open-exits") and prints the server's response.
+func adminCommand(args []string) {
+    if len(args) == 0 || len(args) > 2 {
+        log.Fatal("usage: client -admin <command> [argument]")
+    }
+    conn, err := net.Dial("unix", defaultAdminSocket())
+    if err != nil {
+        log.Fatalf("dial: %v", err)
+    }
+    defer conn.Close()
+    req := map[string]string{"cmd": args[0]}
+    if len(args) == 2 {
+        req["arg"] = args[1]
+    }
+    if err := nnet.WriteFrame(conn, req); err != nil {
+        log.Fatalf("admin write: %v", err)
+    }
+    var resp struct {
+        OK    bool            `json:"ok"`
+        Error string          `json:"error"`
+        Data  json.RawMessage `json:"data"`
+    }
+    if err := nnet.ReadFrame(conn, &resp); err != nil {
+        log.Fatalf("admin read: %v", err)
+    }
+    if !resp.OK {
+        log.Fatalf("admin: %s", resp.Error)
+    }
+    if len(resp.Data) > 0 {
+        var out bytes.Buffer
+        json.Indent(&out, resp.Data, "", "  ")
+        fmt.Println(out.String())
+    }
+}
+
 func main() {
     socket := defaultSocket()
+    admin := flag.Bool("admin", false, "send the admin command given as arguments (list, kick, pause, resume, spawn, event, snapshot) and exit")
+    watch := flag.Bool("spectate", false, "watch the run through the director camera instead of playing")
+    viewW := flag.Int("view-width", 40, "spectator window width")
+    viewH := flag.Int("view-height", 15, "spectator window height")
     flag.Parse()
 
+    if *admin {
+        adminCommand(flag.Args())
+        return
+    }
+
     conn, err := net.Dial("unix", socket)
     if err != nil {
         log.Fatalf("dial: %v", err)
     }
     defer conn.Close()
 
+    if *watch {
+        spectate(conn, *viewW, *viewH)
+        return
+    }
+
     // Ensure ed25519 identity exists and derive AgentID (base64 public key).
     pub, _, pubB64, err := persist.EnsureIdentity()
     if err != nil {
@@ -60,7 +189,7 @@ func main() {
                 return
             }
             if m["type"] == "obs" {
-                fmt.Printf("Tick %v Visible: %v\n", m["tick"], m["visible"])
+                fmt.Printf("Tick %v Radius %v Entities: %v Sounds: %v Visible: %v\n", m["tick"], m["radius"], m["entities"], m["sounds"], m["visible"])
             }
         }
     }()
@@ -69,6 +198,8 @@ func main() {
     stdin := bufio.NewScanner(os.Stdin)
     for stdin.Scan() {
         key := stdin.Text()
-        _ = nnet.WriteFrame(conn, inputMsg{Type: "input", Key: key})
+        if err := nnet.WriteFrame(conn, inputMsg{Type: "input", Key: key}); err != nil {
+            log.Fatalf("input write: %v", err)
+        }
     }
 }
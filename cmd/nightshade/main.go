@@ -1,17 +1,110 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"time"
+
 	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/persist"
 	"github.com/divijg19/Nightshade/internal/runtime"
+	"github.com/divijg19/Nightshade/internal/world"
 )
 
 func main() {
+	cfg := runtime.DefaultConfig()
+	layoutName := flag.String("layout", string(world.LayoutEmpty), "stage layout: empty, rooms, caves or arena")
+	spawnName := flag.String("spawn", string(runtime.SpawnPoints), "spawn strategy: points, row or scatter")
+	mapPath := flag.String("map", "", "load a hand-authored stage file instead of generating one")
+	profilesPath := flag.String("profiles", "", "cognition profiles file assigning parameters to entities by id")
+	storeName := flag.String("store", persist.StoreDir, "agent store: dir (one directory per agent) or log (single append-only file)")
+	flag.IntVar(&cfg.Width, "width", cfg.Width, "stage width for generated layouts")
+	flag.IntVar(&cfg.Height, "height", cfg.Height, "stage height for generated layouts")
+	flag.IntVar(&cfg.VisibilityRadius, "radius", cfg.VisibilityRadius, "visibility radius")
+	flag.Uint64Var(&cfg.Seed, "seed", 0, "run seed (0 picks one from the clock)")
+	flag.Parse()
+
+	var err error
+	if cfg.Layout, err = world.ParseLayout(*layoutName); err != nil {
+		log.Fatal(err)
+	}
+	if cfg.Spawn, err = runtime.ParseSpawnStrategy(*spawnName); err != nil {
+		log.Fatal(err)
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = uint64(time.Now().UnixNano())
+	}
+	cfg.Gen = world.DefaultGenOptions
+	if *mapPath != "" {
+		if cfg.World, err = world.LoadMap(*mapPath); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("map %s seed %d", *mapPath, cfg.Seed)
+	} else {
+		log.Printf("layout %s seed %d", cfg.Layout, cfg.Seed)
+	}
+	if *profilesPath != "" {
+		if cfg.Profiles, err = agent.LoadProfiles(*profilesPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	store, err := persist.OpenAgentStore(*storeName)
+	if err != nil {
+		log.Fatal(err)
+	}
 	human := agent.NewHuman("You")
+	rec, err := store.Load(human.ID())
+	switch {
+	case err == nil:
+		human.Restore(rec.State())
+		if p, ok := rec.PositionValue(); ok {
+			cfg.Positions = map[string]core.Position{human.ID(): p}
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		log.Fatalf("load %s: %v", human.ID(), err)
+	}
 	npc := agent.NewOscillating("B")
+	seeker := agent.NewSeeker("C", core.Position{})
+
+	rt, err := runtime.NewWithConfig([]agent.Agent{human, npc, seeker}, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var goal core.Position
+	if zones := rt.ZoneNames(); len(zones) > 0 {
+		goal, _ = rt.ZoneCenter(zones[0])
+	} else {
+		c := core.Position{X: rt.Config().Width / 2, Y: rt.Config().Height / 2}
+		rt.AddZone("center", core.Position{X: c.X - 2, Y: c.Y - 1}, core.Position{X: c.X + 2, Y: c.Y + 1})
+		goal, _ = rt.ZoneCenter("center")
+	}
+	seeker.SetGoal(goal)
 
-	rt := runtime.New([]agent.Agent{human, npc})
+	rt.AddWinCondition(runtime.Survival{})
+	rt.AddWinCondition(runtime.Extraction{})
+	rt.AddWinCondition(runtime.Control{Ticks: 100})
+	rt.AddWinCondition(runtime.TickLimit{Ticks: 300})
 
-	for i := 0; i < 300; i++ {
+	for rt.State() != runtime.RunEnded {
 		_ = rt.TickOnce()
 	}
+
+	res, _ := rt.Result()
+	runID := time.Now().UTC().Format("20060102T150405Z")
+	if err := persist.WriteRunResult(runID, res); err != nil {
+		log.Printf("run result: %v", err)
+	}
+	var pos *core.Position
+	if p, ok := rt.PositionOf(human.ID()); ok {
+		pos = &p
+	}
+	if err := store.Save(human.ID(), persist.NewAgentRecord(human.State(), pos, runID)); err != nil {
+		log.Printf("save %s: %v", human.ID(), err)
+	}
+	fmt.Println("Run complete. Data retained.")
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+	nnet "github.com/divijg19/Nightshade/internal/net"
+	"github.com/divijg19/Nightshade/internal/runtime"
+)
+
+// adminRequest is one command on the admin socket:
+//
+//	list                       connected agents and every active entity
+//	kick <id>                  remove an entity from the run and drop its client
+//	pause, resume              stop or restart the clock
+//	spawn <scripted|oscillating>  add an NPC
+//	event <open-exits|close-exits|place-exit>
+//	snapshot <id>              what entity id perceives right now
+type adminRequest struct {
+	Cmd string `json:"cmd"`
+	Arg string `json:"arg,omitempty"`
+}
+
+type adminResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+	Data  any    `json:"data,omitempty"`
+}
+
+// adminStatus answers list.
+type adminStatus struct {
+	Running   bool                   `json:"running"`
+	Tick      int                    `json:"tick"`
+	State     string                 `json:"state"`
+	Paused    bool                   `json:"paused"`
+	Connected []string               `json:"connected"`
+	Entities  []runtime.EntityStatus `json:"entities"`
+}
+
+// adminSnapshot answers snapshot.
+type adminSnapshot struct {
+	Snapshot runtime.Snapshot `json:"snapshot"`
+	Marker   core.Position    `json:"marker"`
+}
+
+var errNoRun = errors.New("no run in progress")
+
+// handleAdmin serves admin requests on conn, one response per request,
+// until the client hangs up.
+func (s *server) handleAdmin(conn net.Conn) {
+	defer conn.Close()
+	lg := nnet.ConnLogger(s.log).With("admin", true)
+	for {
+		var req adminRequest
+		if err := nnet.ReadFrame(conn, &req); err != nil {
+			return
+		}
+		data, err := s.admin(req)
+		resp := adminResponse{OK: err == nil, Data: data}
+		if err != nil {
+			resp.Error = err.Error()
+			lg.Warn("admin command failed", "cmd", req.Cmd, "arg", req.Arg, "err", err)
+		} else if req.Cmd != "list" && req.Cmd != "snapshot" {
+			lg.Info("admin command", "cmd", req.Cmd, "arg", req.Arg)
+		}
+		if err := nnet.WriteFrame(conn, resp); err != nil {
+			return
+		}
+	}
+}
+
+func (s *server) admin(req adminRequest) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.Cmd == "list" {
+		return s.status(), nil
+	}
+	if s.loop == nil {
+		return nil, errNoRun
+	}
+	switch req.Cmd {
+	case "kick":
+		var pos core.Position
+		var kicked bool
+		s.loop.Do(func(rt *runtime.Runtime) {
+			pos, _ = rt.PositionOf(req.Arg)
+			kicked = rt.Kick(req.Arg)
+		})
+		if !kicked {
+			return nil, fmt.Errorf("no active entity %q", req.Arg)
+		}
+		// A kicked player is no longer in the run: save it where it stood,
+		// like an agent waiting for the next run, and drop its connection.
+		if _, ok := s.agents[req.Arg]; ok {
+			delete(s.members, req.Arg)
+			s.positions[req.Arg] = pos
+		}
+		if c, ok := s.conns[req.Arg]; ok {
+			c.Close()
+			delete(s.conns, req.Arg)
+		}
+		return nil, nil
+	case "pause", "resume":
+		s.loop.SetPaused(req.Cmd == "pause")
+		return nil, nil
+	case "spawn":
+		var newNPC func(id string) agent.Agent
+		switch req.Arg {
+		case "scripted":
+			newNPC = func(id string) agent.Agent { return agent.NewScripted(id) }
+		case "oscillating":
+			newNPC = func(id string) agent.Agent { return agent.NewOscillating(id) }
+		default:
+			return nil, fmt.Errorf("unknown NPC kind %q (want scripted or oscillating)", req.Arg)
+		}
+		s.npcs++
+		id := fmt.Sprintf("npc-%s-%d", req.Arg, s.npcs)
+		a := newNPC(id)
+		var pos core.Position
+		var err error
+		s.loop.Do(func(rt *runtime.Runtime) { pos, err = rt.Spawn(a) })
+		if err != nil {
+			return nil, err
+		}
+		return runtime.EntityStatus{ID: id, Position: pos, Energy: agent.MaxEnergy}, nil
+	case "event":
+		var err error
+		s.loop.Do(func(rt *runtime.Runtime) { err = rt.Trigger(runtime.WorldEvent(req.Arg)) })
+		return nil, err
+	case "snapshot":
+		var out adminSnapshot
+		var ok bool
+		s.loop.Do(func(rt *runtime.Runtime) {
+			out.Snapshot, ok = rt.SnapshotForDebug(req.Arg)
+			out.Marker = rt.MarkerPosition()
+		})
+		if !ok {
+			return nil, fmt.Errorf("no active entity %q", req.Arg)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unknown command %q", req.Cmd)
+}
+
+// status reports connected clients and, once the run has started, every
+// active entity. Callers hold s.mu.
+func (s *server) status() adminStatus {
+	st := adminStatus{Connected: []string{}, Entities: []runtime.EntityStatus{}}
+	for id := range s.conns {
+		st.Connected = append(st.Connected, id)
+	}
+	sort.Strings(st.Connected)
+	if s.loop == nil {
+		return st
+	}
+	st.Running = true
+	st.Paused = s.loop.Paused()
+	s.loop.Do(func(rt *runtime.Runtime) {
+		st.Tick = rt.Tick()
+		st.State = rt.State().String()
+		st.Entities = rt.Status()
+	})
+	return st
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/runtime"
+)
+
+// debugDump answers /debug/snapshot: the marker and what each requested
+// entity perceives.
+type debugDump struct {
+	Tick      int                         `json:"tick"`
+	Marker    core.Position               `json:"marker"`
+	Snapshots map[string]runtime.Snapshot `json:"snapshots"`
+}
+
+// debugHandler serves the local debug endpoint:
+//
+//	/metrics           Prometheus text format
+//	/debug/pprof/      the standard profiles
+//	/debug/snapshot    marker and snapshots as JSON; ?id= limits it to one entity
+//
+// It exposes every agent's view of the run, so it is only ever bound to a
+// loopback address; see checkDebugAddr.
+func (s *server) debugHandler(metrics *runtime.Metrics) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := metrics.WritePrometheus(w); err != nil {
+			s.log.Warn("cannot write metrics", "err", err)
+		}
+	})
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		loop := s.loop
+		s.mu.Unlock()
+		if loop == nil {
+			http.Error(w, errNoRun.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		id := r.URL.Query().Get("id")
+		dump := debugDump{Snapshots: map[string]runtime.Snapshot{}}
+		loop.Do(func(rt *runtime.Runtime) {
+			dump.Tick = rt.Tick()
+			dump.Marker = rt.MarkerPosition()
+			for _, active := range rt.ActiveIDs() {
+				if id != "" && active != id {
+					continue
+				}
+				dump.Snapshots[active], _ = rt.SnapshotForDebug(active)
+			}
+		})
+		if id != "" && len(dump.Snapshots) == 0 {
+			http.Error(w, "no active entity "+id, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dump); err != nil {
+			s.log.Warn("cannot write debug snapshot", "err", err)
+		}
+	})
+	return mux
+}
+
+// lookupHost resolves debug address host names. Tests replace it.
+var lookupHost = net.LookupHost
+
+// checkDebugAddr accepts only host:port addresses whose host is a loopback
+// IP or a name, such as localhost, that resolves to nothing but loopback
+// IPs. An empty host such as ":6060" would listen on every interface and is
+// refused.
+func checkDebugAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	ips := []string{host}
+	if net.ParseIP(host) == nil && host != "" {
+		if ips, err = lookupHost(host); err != nil {
+			return fmt.Errorf("debug address %q: %w", addr, err)
+		}
+	}
+	for _, s := range ips {
+		if ip := net.ParseIP(s); ip == nil || !ip.IsLoopback() {
+			return fmt.Errorf("debug address %q is not a loopback address", addr)
+		}
+	}
+	return nil
+}
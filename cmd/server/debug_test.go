@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckDebugAddr_OnlyLoopback(t *testing.T) {
+	defer func(orig func(string) ([]string, error)) { lookupHost = orig }(lookupHost)
+	hosts := map[string][]string{
+		"localhost":   {"127.0.0.1", "::1"},
+		"example.com": {"93.184.216.34"},
+		"mixed.test":  {"127.0.0.1", "10.0.0.7"},
+	}
+	lookupHost = func(host string) ([]string, error) {
+		if addrs, ok := hosts[host]; ok {
+			return addrs, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	for _, addr := range []string{"127.0.0.1:6060", "localhost:6060", "[::1]:6060", "127.0.0.2:0"} {
+		if err := checkDebugAddr(addr); err != nil {
+			t.Errorf("checkDebugAddr(%q) = %v", addr, err)
+		}
+	}
+	for _, addr := range []string{":6060", "0.0.0.0:6060", "[::]:6060", "192.168.1.4:6060", "example.com:6060", "mixed.test:6060", "unknown.test:6060", "6060"} {
+		if err := checkDebugAddr(addr); err == nil {
+			t.Errorf("checkDebugAddr(%q) accepted a non-loopback address", addr)
+		}
+	}
+
+	// localhost is only trusted as far as it resolves.
+	hosts["localhost"] = []string{"192.168.1.4"}
+	if err := checkDebugAddr("localhost:6060"); err == nil {
+		t.Error("checkDebugAddr accepted a localhost that resolves off the machine")
+	}
+}
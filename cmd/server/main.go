@@ -3,11 +3,20 @@ package main
 import (
 	"bufio"
 	"encoding/base64"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/divijg19/Nightshade/internal/agent"
@@ -15,6 +24,7 @@ import (
 	nnet "github.com/divijg19/Nightshade/internal/net"
 	"github.com/divijg19/Nightshade/internal/persist"
 	"github.com/divijg19/Nightshade/internal/runtime"
+	"github.com/divijg19/Nightshade/internal/world"
 )
 
 type helloMsg struct {
@@ -27,6 +37,186 @@ type inputMsg struct {
 	Key  string `json:"key"`
 }
 
+// loadAgent rehydrates agent id from store. Unknown agents start fresh; a
+// save that cannot be read is an error so the caller can refuse the agent
+// rather than overwrite its memory.
+func loadAgent(store persist.AgentStore, id string) (*agent.RemoteHuman, *core.Position, error) {
+	rec, err := store.Load(id)
+	if errors.Is(err, fs.ErrNotExist) {
+		return agent.NewRemoteHumanFromExisting(id, agent.NewMemory(), agent.MaxEnergy), nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	var pos *core.Position
+	if p, ok := rec.PositionValue(); ok {
+		pos = &p
+	}
+	return agent.NewRemoteHumanFromState(id, rec.State()), pos, nil
+}
+
+// restoredPosition returns the saved position of an agent that has not yet
+// joined a run, so saving does not forget it.
+func restoredPosition(positions map[string]core.Position, id string) *core.Position {
+	if p, ok := positions[id]; ok {
+		return &p
+	}
+	return nil
+}
+
+// saver flushes agents to the store. collect runs under the server mutex and
+// only copies the state of agents that changed; write runs outside it, so a
+// slow store never holds up the tick loop or new connections. Records that
+// fail to write are retried on the next flush unless a newer copy replaces
+// them.
+type saver struct {
+	store   persist.AgentStore
+	pending map[string]persist.AgentRecord
+	saved   map[string]*core.Position // position in the last record written
+}
+
+func newSaver(store persist.AgentStore) *saver {
+	return &saver{store: store, pending: map[string]persist.AgentRecord{}, saved: map[string]*core.Position{}}
+}
+
+// collect snapshots every agent that is dirty or has moved, and marks it
+// clean. pos reports an agent's current position.
+func (s *saver) collect(agents map[string]*agent.RemoteHuman, pos func(id string) (*core.Position, string)) {
+	for id, a := range agents {
+		p, run := pos(id)
+		last, known := s.saved[id]
+		if known && !a.Dirty() && samePosition(last, p) {
+			continue
+		}
+		s.pending[id] = persist.NewAgentRecord(a.State(), p, run)
+		s.saved[id] = p
+		a.MarkClean()
+	}
+}
+
+// write stores all pending records.
+func (s *saver) write() error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+	if err := persist.SaveAll(s.store, s.pending); err != nil {
+		return err
+	}
+	s.pending = map[string]persist.AgentRecord{}
+	return nil
+}
+
+func samePosition(a, b *core.Position) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// frameMsg is what spectators receive each tick: the whole stage and where
+// the director points the camera.
+type frameMsg struct {
+	Type   string         `json:"type"`
+	Frame  runtime.Frame  `json:"frame"`
+	Camera runtime.Camera `json:"camera"`
+}
+
+// spectators fans frames out to watching connections. broadcast runs on the
+// loop goroutine and never blocks it: a spectator that falls behind misses
+// frames rather than slowing the run.
+type spectators struct {
+	mu   sync.Mutex
+	next int
+	subs map[int]chan frameMsg
+}
+
+func newSpectators() *spectators {
+	return &spectators{subs: map[int]chan frameMsg{}}
+}
+
+func (s *spectators) add() (int, <-chan frameMsg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	ch := make(chan frameMsg, 1)
+	s.subs[s.next] = ch
+	return s.next, ch
+}
+
+func (s *spectators) remove(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ch, ok := s.subs[id]; ok {
+		close(ch)
+		delete(s.subs, id)
+	}
+}
+
+func (s *spectators) empty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subs) == 0
+}
+
+func (s *spectators) broadcast(m frameMsg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.subs {
+		select {
+		case ch <- m:
+		default:
+		}
+	}
+}
+
+// serveSpectator streams frames to conn until it goes away.
+func serveSpectator(conn net.Conn, specs *spectators, lg *slog.Logger) {
+	id, frames := specs.add()
+	defer specs.remove(id)
+	// Spectators send nothing; a failed read means they hung up.
+	gone := make(chan struct{})
+	go func() {
+		var discard map[string]any
+		for nnet.ReadFrame(conn, &discard) == nil {
+		}
+		close(gone)
+	}()
+	for {
+		select {
+		case m := <-frames:
+			if err := nnet.WriteFrame(conn, m); err != nil {
+				lg.Info("frame write failed", "tick", m.Frame.Tick, "err", err)
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
+
+// newLogger builds the server logger from the -log-level and -log-format
+// flags.
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("bad -log-level %q: want debug, info, warn or error", level)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("bad -log-format %q: want text or json", format)
+}
+
+// fatal logs msg at error level and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
 func defaultSocket() string {
 	if s := os.Getenv("NIGHTSHADE_SOCKET"); s != "" {
 		return s
@@ -34,83 +224,138 @@ func defaultSocket() string {
 	return filepath.Join(persist.BaseDir(), "socket")
 }
 
-	func handleConn(conn net.Conn, agents map[string]*agent.RemoteHuman, mu *sync.Mutex) {
+func defaultAdminSocket() string {
+	if s := os.Getenv("NIGHTSHADE_ADMIN_SOCKET"); s != "" {
+		return s
+	}
+	return filepath.Join(persist.BaseDir(), "admin.socket")
+}
+
+// server holds state shared by connection handlers, the admin channel and
+// the persistence loop. cfg.Metrics and cfg.Logger must be set. mu guards
+// the maps and loop; once the run has started, the agents taking part
+// belong to the loop and are only touched through loop.Do.
+type server struct {
+	store persist.AgentStore
+	specs *spectators
+	runID string
+	log   *slog.Logger
+
+	players    *runtime.Gauge
+	spectating *runtime.Gauge
+
+	cfg      runtime.Config
+	maxTicks int
+	mapPath  string
+
+	mu        sync.Mutex
+	agents    map[string]*agent.RemoteHuman
+	positions map[string]core.Position
+	members   map[string]bool
+	conns     map[string]net.Conn
+	loop      *runtime.Loop
+	npcs      int
+}
+
+func newServer(store persist.AgentStore, runID string, cfg runtime.Config, maxTicks int, mapPath string) *server {
+	return &server{
+		store:     store,
+		specs:     newSpectators(),
+		runID:     runID,
+		cfg:       cfg,
+		maxTicks:  maxTicks,
+		mapPath:   mapPath,
+		agents:    map[string]*agent.RemoteHuman{},
+		positions: map[string]core.Position{},
+		members:   map[string]bool{},
+		conns:     map[string]net.Conn{},
+		log:       cfg.Logger,
+
+		players:    cfg.Metrics.NewGauge("nightshade_player_connections", "Connected player clients."),
+		spectating: cfg.Metrics.NewGauge("nightshade_spectator_connections", "Connected spectators."),
+	}
+}
+
+func (s *server) handleConn(conn net.Conn) {
 	defer conn.Close()
+	lg := nnet.ConnLogger(s.log)
 	// Read hello
 	var h helloMsg
 	if err := nnet.ReadFrame(conn, &h); err != nil {
-		log.Printf("hello read: %v", err)
+		lg.Warn("hello read failed", "err", err)
+		return
+	}
+	if h.Type == "spectate" {
+		lg.Info("spectator connected")
+		s.spectating.Add(1)
+		defer s.spectating.Add(-1)
+		serveSpectator(conn, s.specs, lg)
+		lg.Info("spectator disconnected")
 		return
 	}
 	// Validate that PublicKey is base64 and of correct length for ed25519
 	pubB64 := h.PublicKey
 	if pubB64 == "" {
-		log.Printf("empty public key from client")
+		lg.Warn("empty public key from client")
 		return
 	}
 	pubBytes, err := base64.StdEncoding.DecodeString(pubB64)
 	if err != nil {
-		log.Printf("invalid base64 public key: %v", err)
+		lg.Warn("invalid base64 public key", "err", err)
 		return
 	}
 	if len(pubBytes) != 32 {
-		log.Printf("invalid public key length: %d", len(pubBytes))
+		lg.Warn("invalid public key length", "len", len(pubBytes))
 		return
 	}
 
 	// AgentID is the base64(public key) string
 	agentID := pubB64
-	mu.Lock()
-	rh, ok := agents[agentID]
-	mu.Unlock()
+	lg = lg.With("agent", agentID)
+	s.mu.Lock()
+	rh, ok := s.agents[agentID]
+	s.mu.Unlock()
 	if !ok {
 		// Attempt to rehydrate persisted agent state from disk.
-		agentDir := filepath.Join(persist.BaseDir(), "agents", agentID)
-		var energy int = agent.MaxEnergy
-		mem := agent.NewMemory()
-
-		// Load state.json (energy)
-		statePath := filepath.Join(agentDir, "state.json")
-		var st struct{ Energy int `json:"energy"` }
-		if err := persist.ReadJSON(statePath, &st); err == nil {
-			energy = st.Energy
-		}
-
-		// Load memory.json (tiles)
-		memoryPath := filepath.Join(agentDir, "memory.json")
-		var raw struct {
-			Tiles []struct {
-				X int `json:"x"`
-				Y int `json:"y"`
-				Glyph int `json:"glyph"`
-				LastSeen int `json:"lastSeen"`
-				ScarLevel int `json:"scarLevel"`
-			} `json:"tiles"`
-		}
-		if err := persist.ReadJSON(memoryPath, &raw); err == nil {
-			for _, t := range raw.Tiles {
-				pos := core.Position{X: t.X, Y: t.Y}
-				mt := agent.MemoryTile{
-					Tile: core.TileView{Position: pos, Glyph: rune(t.Glyph), Visible: true},
-					LastSeen: t.LastSeen,
-					ScarLevel: t.ScarLevel,
-				}
-				mem.SetMemoryTile(pos, mt)
-			}
+		var pos *core.Position
+		rh, pos, err = loadAgent(s.store, agentID)
+		if err != nil {
+			lg.Error("cannot load agent; refusing connection so its save is kept", "err", err)
+			return
 		}
-
-		rh = agent.NewRemoteHumanFromExisting(agentID, mem, energy)
-		mu.Lock()
-		agents[agentID] = rh
-		mu.Unlock()
+		s.mu.Lock()
+		s.agents[agentID] = rh
+		if pos != nil {
+			s.positions[agentID] = *pos
+		}
+		s.mu.Unlock()
 	}
+	s.mu.Lock()
+	s.conns[agentID] = conn
+	s.mu.Unlock()
+	lg.Info("player connected", "reconnect", ok)
+	s.players.Add(1)
+	defer s.players.Add(-1)
+	// The first agent to connect starts the run.
+	s.startRun()
+	defer func() {
+		s.mu.Lock()
+		if s.conns[agentID] == conn {
+			delete(s.conns, agentID)
+		}
+		s.mu.Unlock()
+	}()
 
-	// Start writer goroutine to push observations to client
+	// Start writer goroutine to push observations to client. It stops at
+	// the first failed write: the connection is gone, and a reconnect
+	// starts a writer of its own.
 	go func() {
 		for obs := range rh.SendObservation {
-			out := map[string]interface{}{"type": "obs", "visible": obs.Visible, "tick": obs.Tick}
-			// best-effort write
-			_ = nnet.WriteFrame(conn, out)
+			out := map[string]interface{}{"type": "obs", "visible": obs.Visible, "tick": obs.Tick, "radius": obs.Radius, "entities": obs.Entities, "sounds": obs.Sounds}
+			if err := nnet.WriteFrame(conn, out); err != nil {
+				lg.Info("observation write failed; writer stopping", "tick", obs.Tick, "err", err)
+				return
+			}
 		}
 	}()
 
@@ -119,6 +364,11 @@ func defaultSocket() string {
 	for {
 		var im inputMsg
 		if err := nnet.ReadFrame(dec, &im); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				lg.Info("player disconnected")
+			} else {
+				lg.Warn("player disconnected", "err", err)
+			}
 			return
 		}
 		if im.Type == "input" {
@@ -131,82 +381,228 @@ func defaultSocket() string {
 	}
 }
 
+// startRun builds the runtime from every agent connected so far, plus one
+// oscillating NPC so the world moves, and starts the loop. It does nothing
+// once a run has started or while nobody is connected.
+func (s *server) startRun() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loop != nil || len(s.agents) == 0 {
+		return
+	}
+	// Build agent slice
+	list := make([]agent.Agent, 0, len(s.agents)+1)
+	for id, a := range s.agents {
+		list = append(list, a)
+		s.members[id] = true
+	}
+	list = append(list, agent.NewOscillating("npc-osc"))
+	cfg := s.cfg
+	s.log.Info("run starting", "run", s.runID, "layout", cfg.Layout, "map", s.mapPath, "seed", cfg.Seed)
+	cfg.Positions = s.positions
+	rt, err := runtime.NewWithConfig(list, cfg)
+	if err != nil {
+		fatal("cannot build runtime", "err", err)
+	}
+	rt.AddWinCondition(runtime.Survival{})
+	rt.AddWinCondition(runtime.Extraction{})
+	rt.AddWinCondition(runtime.TickLimit{Ticks: s.maxTicks})
+	rt.OnEnd(func(res runtime.RunResult) {
+		if err := persist.WriteRunResult(s.runID, res); err != nil {
+			s.log.Error("cannot write run result", "run", s.runID, "err", err)
+		}
+		s.log.Info("Run complete. Data retained.", "run", s.runID)
+	})
+	// The director follows the run every tick so the camera is settled
+	// whenever a spectator tunes in.
+	director := runtime.NewDirector()
+	rt.OnTick(func(rt *runtime.Runtime) {
+		cam := director.Update(rt)
+		if !s.specs.empty() {
+			s.specs.broadcast(frameMsg{Type: "frame", Frame: rt.Frame(), Camera: cam})
+		}
+	})
+	rt.Start()
+	s.loop = runtime.NewLoop(rt)
+	go s.loop.Run()
+}
+
+// flush writes every changed agent to the store.
+func (s *server) flush(sv *saver) {
+	// Copy what the saver needs and let go of mu before waiting on the
+	// loop, so a slow tick never stalls connects, kicks or the admin channel.
+	s.mu.Lock()
+	agents, members, positions := maps.Clone(s.agents), maps.Clone(s.members), maps.Clone(s.positions)
+	loop := s.loop
+	s.mu.Unlock()
+	if loop == nil {
+		sv.collect(agents, func(id string) (*core.Position, string) {
+			return restoredPosition(positions, id), ""
+		})
+	} else {
+		// Agents in the run are mutated by the tick; copy them between
+		// ticks on the loop goroutine.
+		loop.Do(func(rt *runtime.Runtime) {
+			sv.collect(agents, func(id string) (*core.Position, string) {
+				if !members[id] {
+					return restoredPosition(positions, id), ""
+				}
+				if p, ok := rt.PositionOf(id); ok {
+					return &p, s.runID
+				}
+				return nil, s.runID
+			})
+		})
+	}
+	if err := sv.write(); err != nil {
+		s.log.Error("cannot save agents; will retry", "pending", len(sv.pending), "err", err)
+	}
+}
+
+// listen opens a unix socket at path, replacing any stale one.
+func listen(path string) (net.Listener, error) {
+	os.Remove(path)
+	return net.Listen("unix", path)
+}
+
+// accept hands every connection on l to handle until l is closed.
+func accept(l net.Listener, handle func(net.Conn), lg *slog.Logger) {
+	for {
+		c, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			lg.Warn("accept failed", "addr", l.Addr(), "err", err)
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		go handle(c)
+	}
+}
+
 func main() {
+	cfg := runtime.DefaultConfig()
+	maxTicks := flag.Int("ticks", 0, "end the run after this many ticks (0 runs until one entity remains)")
+	layoutName := flag.String("layout", string(world.LayoutEmpty), "stage layout: empty, rooms, caves or arena")
+	spawnName := flag.String("spawn", string(runtime.SpawnPoints), "spawn strategy: points, row or scatter")
+	mapPath := flag.String("map", "", "load a hand-authored stage file instead of generating one")
+	profilesPath := flag.String("profiles", "", "cognition profiles file assigning parameters to entities by id")
+	storeName := flag.String("store", persist.StoreDir, "agent store: dir (one directory per agent) or log (single append-only file)")
+	debugAddr := flag.String("debug-addr", "", "serve metrics, pprof and snapshot dumps over HTTP on this address, e.g. 127.0.0.1:6060 (off when empty)")
+	flag.IntVar(&cfg.Width, "width", cfg.Width, "stage width for generated layouts")
+	flag.IntVar(&cfg.Height, "height", cfg.Height, "stage height for generated layouts")
+	flag.IntVar(&cfg.VisibilityRadius, "radius", cfg.VisibilityRadius, "visibility radius")
+	flag.DurationVar(&cfg.TickRate, "tick-rate", cfg.TickRate, "interval between ticks")
+	flag.Uint64Var(&cfg.Seed, "seed", 0, "run seed (0 picks one from the clock)")
+	logLevel := flag.String("log-level", "info", "log level: debug, info, warn or error")
+	logFormat := flag.String("log-format", "text", "log format: text or json")
+	flag.Parse()
+
+	lg, err := newLogger(os.Stderr, *logLevel, *logFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	// Packages that log without a logger of their own (persist) use the
+	// default.
+	slog.SetDefault(lg)
+	cfg.Logger = lg
+
+	if cfg.Layout, err = world.ParseLayout(*layoutName); err != nil {
+		fatal("bad -layout", "err", err)
+	}
+	if cfg.Spawn, err = runtime.ParseSpawnStrategy(*spawnName); err != nil {
+		fatal("bad -spawn", "err", err)
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = uint64(time.Now().UnixNano())
+	}
+	cfg.Gen = world.DefaultGenOptions
+	if *mapPath != "" {
+		if cfg.World, err = world.LoadMap(*mapPath); err != nil {
+			fatal("cannot load map", "err", err)
+		}
+	}
+	if *profilesPath != "" {
+		if cfg.Profiles, err = agent.LoadProfiles(*profilesPath); err != nil {
+			fatal("cannot load profiles", "err", err)
+		}
+	}
+
+	if *debugAddr != "" {
+		if err := checkDebugAddr(*debugAddr); err != nil {
+			fatal("bad -debug-addr", "err", err)
+		}
+	}
+
+	store, err := persist.OpenAgentStore(*storeName)
+	if err != nil {
+		fatal("cannot open agent store", "store", *storeName, "err", err)
+	}
+	cfg.Metrics = runtime.NewMetrics()
+	s := newServer(store, time.Now().UTC().Format("20060102T150405Z"), cfg, *maxTicks, *mapPath)
+	if *debugAddr != "" {
+		go func() {
+			lg.Info("debug endpoint listening", "url", "http://"+*debugAddr+"/")
+			if err := http.ListenAndServe(*debugAddr, s.debugHandler(cfg.Metrics)); err != nil {
+				lg.Error("debug endpoint stopped", "err", err)
+			}
+		}()
+	}
+
 	socket := defaultSocket()
-	os.Remove(socket)
-	l, err := net.Listen("unix", socket)
+	l, err := listen(socket)
 	if err != nil {
-		log.Fatalf("listen: %v", err)
+		fatal("cannot listen", "socket", socket, "err", err)
 	}
 	defer l.Close()
-	log.Printf("server listening on %s", socket)
+	lg.Info("server listening", "socket", socket)
 
-	agents := map[string]*agent.RemoteHuman{}
-	var mu sync.Mutex
-	started := false
-	var rt *runtime.Runtime
+	// The admin socket is readable and writable by the server's user only;
+	// filesystem permissions are its authentication.
+	adminSocket := defaultAdminSocket()
+	al, err := listen(adminSocket)
+	if err == nil {
+		err = os.Chmod(adminSocket, 0o600)
+	}
+	if err != nil {
+		fatal("cannot listen", "socket", adminSocket, "err", err)
+	}
+	defer al.Close()
+	lg.Info("admin listening", "socket", adminSocket)
 
-	// accept loop
-	go func() {
-		for {
-			c, err := l.Accept()
-			if err != nil {
-				log.Printf("accept: %v", err)
-				time.Sleep(100 * time.Millisecond)
-				continue
-			}
-			go handleConn(c, agents, &mu)
-
-			// If runtime not started and we have at least one agent, start it.
-			mu.Lock()
-			if !started && len(agents) > 0 {
-				started = true
-				// Build agent slice
-				list := make([]agent.Agent, 0, len(agents)+1)
-				for _, a := range agents {
-					list = append(list, a)
-				}
-				// Add one oscillating NPC so world moves
-				list = append(list, agent.NewOscillating("npc-osc"))
-				rt = runtime.New(list)
-				// Start tick loop honoring existing runtime.TickOnce
-				go func() {
-					for {
-						_ = rt.TickOnce()
-						time.Sleep(200 * time.Millisecond)
-					}
-				}()
-			}
-			mu.Unlock()
-		}
-	}()
+	go accept(l, s.handleConn, lg)
+	go accept(al, s.handleAdmin, lg)
 
-	// simple persistence loop: flush agents to disk periodically
+	// Persistence: flush changed agents to the store periodically, and once
+	// more on SIGINT/SIGTERM before exiting.
+	sv := newSaver(store)
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
 	for {
-		mu.Lock()
-		for id, a := range agents {
-			agentDir := filepath.Join(persist.BaseDir(), "agents", id)
-			// persist state.json
-			st := map[string]interface{}{"energy": a.Energy()}
-			_ = persist.WriteJSON(filepath.Join(agentDir, "state.json"), st)
-
-			// persist memory.json in deterministic shape
-			tiles := []map[string]int{}
-			if a.Memory() != nil {
-				for _, mt := range a.Memory().All() {
-					tiles = append(tiles, map[string]int{
-						"x": mt.Tile.Position.X,
-						"y": mt.Tile.Position.Y,
-						"glyph": int(mt.Tile.Glyph),
-						"lastSeen": mt.LastSeen,
-						"scarLevel": mt.ScarLevel,
-					})
-				}
+		select {
+		case <-ticker.C:
+			s.flush(sv)
+		case sig := <-sigs:
+			lg.Info("shutting down", "signal", sig)
+			s.mu.Lock()
+			loop := s.loop
+			s.mu.Unlock()
+			if loop != nil {
+				loop.Do(func(rt *runtime.Runtime) { rt.End("server shutdown") })
 			}
-			memOut := map[string]interface{}{"tiles": tiles}
-			_ = persist.WriteJSON(filepath.Join(agentDir, "memory.json"), memOut)
+			s.flush(sv)
+			if loop != nil {
+				loop.Stop()
+			}
+			l.Close()
+			al.Close()
+			os.Remove(socket)
+			os.Remove(adminSocket)
+			return
 		}
-		mu.Unlock()
-		time.Sleep(1 * time.Second)
 	}
 }
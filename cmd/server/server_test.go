@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/divijg19/Nightshade/internal/core"
+	nnet "github.com/divijg19/Nightshade/internal/net"
+	"github.com/divijg19/Nightshade/internal/persist"
+	"github.com/divijg19/Nightshade/internal/runtime"
+)
+
+func testServer(t *testing.T) (*server, persist.AgentStore) {
+	t.Setenv("NIGHTSHADE_DIR", t.TempDir())
+	cfg := runtime.DefaultConfig()
+	cfg.TickRate = 2 * time.Millisecond
+	cfg.InputTimeout = time.Millisecond
+	cfg.Metrics = runtime.NewMetrics()
+	cfg.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := persist.NewDirStore(t.TempDir())
+	return newServer(store, "test", cfg, 0, ""), store
+}
+
+// dial connects a client to s.handleConn over a pipe, sends hello and
+// counts the frames that come back until the client hangs up.
+func dial(t *testing.T, s *server, wg *sync.WaitGroup, hello helloMsg, frames *int) net.Conn {
+	client, conn := net.Pipe()
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		s.handleConn(conn)
+	}()
+	if err := nnet.WriteFrame(client, hello); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		defer wg.Done()
+		var m map[string]any
+		for nnet.ReadFrame(client, &m) == nil {
+			*frames++
+		}
+	}()
+	return client
+}
+
+// TestServer_ConcurrentClientsAdminAndSaver is meant for go test -race: a
+// player and a spectator are served while the saver flushes and the admin
+// channel lists the run.
+func TestServer_ConcurrentClientsAdminAndSaver(t *testing.T) {
+	s, store := testServer(t)
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+	var clients sync.WaitGroup
+	var observed, watched int
+	player := dial(t, s, &clients, helloMsg{Type: "hello", PublicKey: key}, &observed)
+	spectator := dial(t, s, &clients, helloMsg{Type: "spectate"}, &watched)
+
+	deadline := time.Now().Add(5 * time.Second)
+	var busy sync.WaitGroup
+	busy.Add(3)
+	go func() {
+		defer busy.Done()
+		sv := newSaver(store)
+		for i := 0; i < 50; i++ {
+			s.flush(sv)
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	go func() {
+		defer busy.Done()
+		for i := 0; i < 200; i++ {
+			if _, err := s.admin(adminRequest{Cmd: "list"}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer busy.Done()
+		for _, k := range []string{"d", "s", "a", "w", "d", "s"} {
+			if err := nnet.WriteFrame(player, inputMsg{Type: "input", Key: k}); err != nil {
+				t.Error(err)
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+	busy.Wait()
+
+	// Let a few more ticks reach both clients.
+	for time.Now().Before(deadline) {
+		st, _ := s.admin(adminRequest{Cmd: "list"})
+		if st.(adminStatus).Tick > 20 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if ids, err := store.List(); err != nil || len(ids) != 1 || ids[0] != key {
+		t.Fatalf("saved agents = %v, %v", ids, err)
+	}
+
+	player.Close()
+	spectator.Close()
+	clients.Wait()
+	s.mu.Lock()
+	loop := s.loop
+	s.mu.Unlock()
+	loop.Stop()
+	if observed == 0 || watched == 0 {
+		t.Fatalf("player got %d observations, spectator %d frames", observed, watched)
+	}
+}
+
+// TestServer_KickedPlayerIsSavedWhereItStood kicks a player, flushes and
+// reconnects it: the save keeps its last position and the reconnect does
+// not put it back in the run.
+func TestServer_KickedPlayerIsSavedWhereItStood(t *testing.T) {
+	s, store := testServer(t)
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+	var clients sync.WaitGroup
+	var observed, again int
+	dial(t, s, &clients, helloMsg{Type: "hello", PublicKey: key}, &observed)
+	var before core.Position
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		st, _ := s.admin(adminRequest{Cmd: "list"})
+		joined := false
+		for _, e := range st.(adminStatus).Entities {
+			if e.ID == key {
+				before, joined = e.Position, true
+			}
+		}
+		if joined {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("player never joined the run")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := s.admin(adminRequest{Cmd: "kick", Arg: key}); err != nil {
+		t.Fatal(err)
+	}
+	clients.Wait()
+
+	sv := newSaver(store)
+	s.flush(sv)
+	rec, err := store.Load(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := rec.PositionValue(); !ok || p != before || rec.Run != "" {
+		t.Fatalf("saved position %v, %v in run %q; want %v", p, ok, rec.Run, before)
+	}
+
+	player := dial(t, s, &clients, helloMsg{Type: "hello", PublicKey: key}, &again)
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		s.mu.Lock()
+		_, connected := s.conns[key]
+		s.mu.Unlock()
+		if connected {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("reconnect never registered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	st, _ := s.admin(adminRequest{Cmd: "list"})
+	for _, e := range st.(adminStatus).Entities {
+		if e.ID == key {
+			t.Fatalf("kicked player is back in the run at %v", e.Position)
+		}
+	}
+	s.flush(sv)
+	if rec, _ := store.Load(key); rec.Position == nil {
+		t.Fatal("flush after reconnect dropped the saved position")
+	}
+
+	player.Close()
+	clients.Wait()
+	s.mu.Lock()
+	loop := s.loop
+	s.mu.Unlock()
+	loop.Stop()
+}
@@ -15,10 +15,10 @@ func TestApplyBeliefContagion_BasicTransfer(t *testing.T) {
 
     // Emit a belief signal for sender at tick 10
     beliefs := []Belief{{Tile: core.TileView{Position: tilePos, Glyph: 'Z', Visible: true}, Age: 0}}
-    emitBeliefSignal("sender", 10, core.Position{X: 0, Y: 0}, beliefs)
+    emitBeliefSignal("sender", 10, core.Position{X: 0, Y: 0}, beliefs, nil)
 
     // Receiver at position within BeliefRadius of sender
-    applied := applyBeliefContagion("receiver", core.Position{X: 1, Y: 0}, 10, receiverMem, MaxEnergy)
+    applied := applyBeliefContagion("receiver", core.Position{X: 1, Y: 0}, 10, receiverMem, MaxEnergy, DefaultProfile())
     if len(applied) == 0 {
         t.Fatalf("expected contagion to apply, none applied")
     }
@@ -19,9 +19,9 @@ func TestBeliefTransfersInRange(t *testing.T) {
     a.Memory().tiles[tilePos] = MemoryTile{Tile: core.TileView{Position: tilePos}, LastSeen: tick}
 
     // Emit A signal
-    emitBeliefSignal(a.ID(), tick, aPos, []Belief{{Tile: core.TileView{Position: tilePos}, Age: 0}})
+    emitBeliefSignal(a.ID(), tick, aPos, []Belief{{Tile: core.TileView{Position: tilePos}, Age: 0}}, nil)
     // Apply contagion to B
-    applied := applyBeliefContagion(b.ID(), bPos, tick, b.Memory(), MaxEnergy)
+    applied := applyBeliefContagion(b.ID(), bPos, tick, b.Memory(), MaxEnergy, DefaultProfile())
     if len(applied) == 0 {
         t.Fatalf("expected belief to transfer in range")
     }
@@ -43,8 +43,8 @@ func TestBeliefDoesNotTransferOutOfRange(t *testing.T) {
     tick := 100
     tilePos := core.Position{X:8, Y:8}
     a.Memory().tiles[tilePos] = MemoryTile{Tile: core.TileView{Position: tilePos}, LastSeen: tick}
-    emitBeliefSignal(a.ID(), tick, aPos, []Belief{{Tile: core.TileView{Position: tilePos}, Age: 0}})
-    applied := applyBeliefContagion(b.ID(), bPos, tick, b.Memory(), MaxEnergy)
+    emitBeliefSignal(a.ID(), tick, aPos, []Belief{{Tile: core.TileView{Position: tilePos}, Age: 0}}, nil)
+    applied := applyBeliefContagion(b.ID(), bPos, tick, b.Memory(), MaxEnergy, DefaultProfile())
     if len(applied) != 0 {
         t.Fatalf("expected no transfer out of range")
     }
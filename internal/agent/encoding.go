@@ -0,0 +1,312 @@
+package agent
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+// Canonical encodings of memory. Persistence, replays, spectators and
+// rollouts all use these, so a belief looks the same wherever it is
+// stored or sent.
+//
+// JSON:
+//
+//	MemoryTile    {"x":3,"y":1,"glyph":35,"lastSeen":9,"scarLevel":0}
+//	Belief        {"x":3,"y":1,"glyph":35,"age":2,"scarLevel":0}
+//	EntityMemory  {"id":"B","position":{"x":5,"y":2},"lastSeen":8,"heading":{"x":1,"y":0},"rumor":true}
+//	Memory        {"tiles":[MemoryTile...],"entities":[EntityMemory...]}
+//
+// Glyphs are encoded as their code point. A remembered tile was seen when
+// it was stored, so decoding sets TileView.Visible. Memory lists tiles in
+// row-major order and entities by id, so equal memories encode to equal
+// bytes.
+//
+// MarshalBinary encodes a Memory in a compact form for replays and
+// rollouts: a format byte, then the tiles and entities in the same order
+// as JSON, with every number a varint and each sighting's Rumor and Stale
+// packed into a flags byte.
+
+type pointJSON struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type tileJSON struct {
+	X         int `json:"x"`
+	Y         int `json:"y"`
+	Glyph     int `json:"glyph"`
+	LastSeen  int `json:"lastSeen"`
+	ScarLevel int `json:"scarLevel"`
+}
+
+// MarshalJSON encodes the tile in its canonical JSON form.
+func (mt MemoryTile) MarshalJSON() ([]byte, error) {
+	p := mt.Tile.Position
+	return json.Marshal(tileJSON{X: p.X, Y: p.Y, Glyph: int(mt.Tile.Glyph), LastSeen: mt.LastSeen, ScarLevel: mt.ScarLevel})
+}
+
+// UnmarshalJSON decodes a tile written by MarshalJSON.
+func (mt *MemoryTile) UnmarshalJSON(b []byte) error {
+	var t tileJSON
+	if err := json.Unmarshal(b, &t); err != nil {
+		return err
+	}
+	pos := core.Position{X: t.X, Y: t.Y}
+	*mt = MemoryTile{Tile: core.TileView{Position: pos, Glyph: rune(t.Glyph), Visible: true}, LastSeen: t.LastSeen, ScarLevel: t.ScarLevel}
+	return nil
+}
+
+type beliefJSON struct {
+	X         int `json:"x"`
+	Y         int `json:"y"`
+	Glyph     int `json:"glyph"`
+	Age       int `json:"age"`
+	ScarLevel int `json:"scarLevel"`
+}
+
+// MarshalJSON encodes the belief in its canonical JSON form.
+func (b Belief) MarshalJSON() ([]byte, error) {
+	p := b.Tile.Position
+	return json.Marshal(beliefJSON{X: p.X, Y: p.Y, Glyph: int(b.Tile.Glyph), Age: b.Age, ScarLevel: b.ScarLevel})
+}
+
+// UnmarshalJSON decodes a belief written by MarshalJSON.
+func (b *Belief) UnmarshalJSON(data []byte) error {
+	var j beliefJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	pos := core.Position{X: j.X, Y: j.Y}
+	*b = Belief{Tile: core.TileView{Position: pos, Glyph: rune(j.Glyph), Visible: true}, Age: j.Age, ScarLevel: j.ScarLevel}
+	return nil
+}
+
+type entityJSON struct {
+	ID       string    `json:"id"`
+	Position pointJSON `json:"position"`
+	LastSeen int       `json:"lastSeen"`
+	Heading  pointJSON `json:"heading"`
+	Rumor    bool      `json:"rumor,omitempty"`
+	Stale    bool      `json:"stale,omitempty"`
+}
+
+// MarshalJSON encodes the sighting in its canonical JSON form.
+func (em EntityMemory) MarshalJSON() ([]byte, error) {
+	return json.Marshal(entityJSON{
+		ID:       em.ID,
+		Position: pointJSON{X: em.Position.X, Y: em.Position.Y},
+		LastSeen: em.LastSeen,
+		Heading:  pointJSON{X: em.Heading.X, Y: em.Heading.Y},
+		Rumor:    em.Rumor,
+		Stale:    em.Stale,
+	})
+}
+
+// UnmarshalJSON decodes a sighting written by MarshalJSON.
+func (em *EntityMemory) UnmarshalJSON(b []byte) error {
+	var j entityJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	*em = EntityMemory{
+		ID:       j.ID,
+		Position: core.Position{X: j.Position.X, Y: j.Position.Y},
+		LastSeen: j.LastSeen,
+		Heading:  core.Position{X: j.Heading.X, Y: j.Heading.Y},
+		Rumor:    j.Rumor,
+		Stale:    j.Stale,
+	}
+	return nil
+}
+
+type memoryJSON struct {
+	Tiles    []MemoryTile   `json:"tiles"`
+	Entities []EntityMemory `json:"entities,omitempty"`
+}
+
+// MarshalJSON encodes the memory in its canonical JSON form.
+func (m *Memory) MarshalJSON() ([]byte, error) {
+	doc := memoryJSON{Tiles: m.All(), Entities: m.Entities()}
+	if doc.Tiles == nil {
+		doc.Tiles = []MemoryTile{}
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the memory's contents with those written by
+// MarshalJSON. The decoded memory is dirty.
+func (m *Memory) UnmarshalJSON(b []byte) error {
+	var doc memoryJSON
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	m.load(doc.Tiles, doc.Entities)
+	return nil
+}
+
+// NewMemoryFrom returns a memory holding tiles and entity sightings, as
+// returned by All and Entities.
+func NewMemoryFrom(tiles []MemoryTile, entities []EntityMemory) *Memory {
+	m := NewMemory()
+	m.load(tiles, entities)
+	return m
+}
+
+// load replaces the contents of m and marks it dirty.
+func (m *Memory) load(tiles []MemoryTile, entities []EntityMemory) {
+	m.tiles = make(map[core.Position]MemoryTile, len(tiles))
+	for _, mt := range tiles {
+		m.tiles[mt.Tile.Position] = mt
+	}
+	m.entities = make(map[string]EntityMemory, len(entities))
+	for _, em := range entities {
+		m.entities[em.ID] = em
+	}
+	m.forgotten = 0
+	m.dirty = true
+}
+
+// memoryFormat is the first byte of the binary encoding.
+const memoryFormat = 1
+
+// Entity sighting flags in the binary encoding.
+const (
+	flagRumor = 1 << iota
+	flagStale
+)
+
+// errMemoryEncoding reports a binary memory that cannot be decoded.
+var errMemoryEncoding = errors.New("agent: malformed memory encoding")
+
+// MarshalBinary encodes the memory in its compact binary form.
+func (m *Memory) MarshalBinary() ([]byte, error) {
+	tiles, entities := m.All(), m.Entities()
+	b := make([]byte, 0, 1+len(tiles)*6+len(entities)*16)
+	b = append(b, memoryFormat)
+	b = binary.AppendUvarint(b, uint64(len(tiles)))
+	for _, mt := range tiles {
+		p := mt.Tile.Position
+		for _, v := range []int{p.X, p.Y, int(mt.Tile.Glyph), mt.LastSeen, mt.ScarLevel} {
+			b = binary.AppendVarint(b, int64(v))
+		}
+	}
+	b = binary.AppendUvarint(b, uint64(len(entities)))
+	for _, em := range entities {
+		b = binary.AppendUvarint(b, uint64(len(em.ID)))
+		b = append(b, em.ID...)
+		for _, v := range []int{em.Position.X, em.Position.Y, em.LastSeen, em.Heading.X, em.Heading.Y} {
+			b = binary.AppendVarint(b, int64(v))
+		}
+		var flags byte
+		if em.Rumor {
+			flags |= flagRumor
+		}
+		if em.Stale {
+			flags |= flagStale
+		}
+		b = append(b, flags)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary replaces the memory's contents with those written by
+// MarshalBinary. The decoded memory is dirty.
+func (m *Memory) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 || b[0] != memoryFormat {
+		return fmt.Errorf("%w: unknown format", errMemoryEncoding)
+	}
+	d := decoder{b: b[1:]}
+	tiles := make([]MemoryTile, d.count(5))
+	for i := range tiles {
+		pos := core.Position{X: d.int(), Y: d.int()}
+		tiles[i] = MemoryTile{Tile: core.TileView{Position: pos, Glyph: rune(d.int()), Visible: true}, LastSeen: d.int(), ScarLevel: d.int()}
+	}
+	entities := make([]EntityMemory, d.count(7))
+	for i := range entities {
+		em := EntityMemory{ID: d.string()}
+		em.Position = core.Position{X: d.int(), Y: d.int()}
+		em.LastSeen = d.int()
+		em.Heading = core.Position{X: d.int(), Y: d.int()}
+		flags := d.byte()
+		em.Rumor, em.Stale = flags&flagRumor != 0, flags&flagStale != 0
+		entities[i] = em
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.b) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", errMemoryEncoding, len(d.b))
+	}
+	m.load(tiles, entities)
+	return nil
+}
+
+// decoder reads the binary memory encoding, remembering the first error so
+// callers can check once at the end.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: truncated", errMemoryEncoding)
+	}
+	d.b = nil
+}
+
+func (d *decoder) uint() uint64 {
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) int() int {
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.b = d.b[n:]
+	return int(v)
+}
+
+func (d *decoder) byte() byte {
+	if len(d.b) == 0 {
+		d.fail()
+		return 0
+	}
+	v := d.b[0]
+	d.b = d.b[1:]
+	return v
+}
+
+func (d *decoder) string() string {
+	n := d.uint()
+	if n > uint64(len(d.b)) {
+		d.fail()
+		return ""
+	}
+	s := string(d.b[:n])
+	d.b = d.b[n:]
+	return s
+}
+
+// count reads an element count. Each element takes at least minBytes, so a
+// count the remaining input cannot hold is rejected before allocating.
+func (d *decoder) count(minBytes int) int {
+	n := d.uint()
+	if n > uint64(len(d.b)/minBytes) {
+		d.fail()
+		return 0
+	}
+	return int(n)
+}
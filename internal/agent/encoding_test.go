@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+func encodingMemory() *Memory {
+	m := NewMemory()
+	rememberAt(m, 3, 1, '#', 9, 0)
+	rememberAt(m, -2, 0, '.', 4, 2)
+	rememberAt(m, 0, 1, 'é', 7, 0)
+	m.SetEntityMemory(EntityMemory{ID: "B", Position: core.Position{X: 5, Y: 2}, LastSeen: 8, Heading: core.Position{X: 1}, Stale: true})
+	m.SetEntityMemory(EntityMemory{ID: "A", Position: core.Position{X: -1, Y: 4}, LastSeen: 6, Rumor: true})
+	return m
+}
+
+func TestMemoryJSON_RoundTripsAndIsCanonical(t *testing.T) {
+	m := encodingMemory()
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"tiles":[{"x":-2,"y":0,"glyph":46,"lastSeen":4,"scarLevel":2},{"x":0,"y":1,"glyph":233,"lastSeen":7,"scarLevel":0},{"x":3,"y":1,"glyph":35,"lastSeen":9,"scarLevel":0}],` +
+		`"entities":[{"id":"A","position":{"x":-1,"y":4},"lastSeen":6,"heading":{"x":0,"y":0},"rumor":true},{"id":"B","position":{"x":5,"y":2},"lastSeen":8,"heading":{"x":1,"y":0},"stale":true}]}`
+	if string(b) != want {
+		t.Fatalf("Memory JSON =\n%s\nwant\n%s", b, want)
+	}
+
+	got := NewMemory()
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Dirty() || !reflect.DeepEqual(got.Entities(), m.Entities()) {
+		t.Fatalf("decoded entities = %+v, dirty = %v", got.Entities(), got.Dirty())
+	}
+	for _, mt := range got.All() {
+		orig, _ := m.GetMemoryTile(mt.Tile.Position)
+		orig.Tile.Visible = true
+		if mt != orig {
+			t.Fatalf("decoded tile %+v, want %+v", mt, orig)
+		}
+	}
+	if again, _ := json.Marshal(got); string(again) != want {
+		t.Fatalf("re-encoded JSON differs:\n%s", again)
+	}
+	if b, _ := json.Marshal(NewMemory()); string(b) != `{"tiles":[]}` {
+		t.Fatalf("empty memory JSON = %s", b)
+	}
+}
+
+func TestBeliefJSON_RoundTrips(t *testing.T) {
+	b := Belief{Tile: core.TileView{Position: core.Position{X: 2, Y: -3}, Glyph: 'X', Visible: true}, Age: 4, ScarLevel: 1}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"x":2,"y":-3,"glyph":88,"age":4,"scarLevel":1}` {
+		t.Fatalf("Belief JSON = %s", data)
+	}
+	var got Belief
+	if err := json.Unmarshal(data, &got); err != nil || got != b {
+		t.Fatalf("decoded %+v, %v; want %+v", got, err, b)
+	}
+}
+
+func TestMemoryBinary_RoundTripsAndMatchesJSON(t *testing.T) {
+	m := encodingMemory()
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := NewMemory()
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := json.Marshal(m)
+	if fromBinary, _ := json.Marshal(got); string(fromBinary) != string(want) {
+		t.Fatalf("binary round trip gave %s, want %s", fromBinary, want)
+	}
+	if js, _ := json.Marshal(m); len(b) >= len(js)/4 {
+		t.Fatalf("binary encoding is %d bytes, JSON %d", len(b), len(js))
+	}
+}
+
+func TestMemoryBinary_RejectsMalformedInput(t *testing.T) {
+	b, _ := encodingMemory().MarshalBinary()
+	for n := 0; n < len(b); n++ {
+		m := encodingMemory()
+		if err := m.UnmarshalBinary(b[:n]); !errors.Is(err, errMemoryEncoding) {
+			t.Fatalf("truncated to %d bytes: err = %v", n, err)
+		}
+		if m.Count() != 3 {
+			t.Fatalf("failed decode changed the memory: %d tiles", m.Count())
+		}
+	}
+	if err := NewMemory().UnmarshalBinary(append(b, 0)); !errors.Is(err, errMemoryEncoding) {
+		t.Fatalf("trailing byte: err = %v", err)
+	}
+	if err := NewMemory().UnmarshalBinary([]byte{memoryFormat, 0xff, 0xff, 0xff, 0x7f}); !errors.Is(err, errMemoryEncoding) {
+		t.Fatalf("huge count: err = %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package agent
+
+import (
+	"sort"
+
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+// inferHorizon is how many ticks an entity is assumed to keep moving along
+// its last heading after it drops out of sight.
+const inferHorizon = 3
+
+// EntityMemory is an agent's last sighting of another entity.
+type EntityMemory struct {
+	ID       string
+	Position core.Position
+	LastSeen int
+
+	// Heading is the step the entity took between its two latest sightings
+	// on consecutive ticks, or zero when it was not seen moving.
+	Heading core.Position
+
+	// Rumor is set when the sighting was adopted from another agent rather
+	// than seen first hand.
+	Rumor bool
+
+	// Stale is set once the agent has looked where the entity was likely to
+	// be and found it gone. A stale sighting is kept for narration but is
+	// neither hallucinated nor shared.
+	Stale bool
+}
+
+// Likely returns where the entity probably is at tick: its last position
+// carried along its heading for up to inferHorizon ticks.
+func (e EntityMemory) Likely(tick int) core.Position {
+	steps := tick - e.LastSeen
+	if steps > inferHorizon {
+		steps = inferHorizon
+	}
+	if steps < 0 {
+		steps = 0
+	}
+	return core.Position{X: e.Position.X + steps*e.Heading.X, Y: e.Position.Y + steps*e.Heading.Y}
+}
+
+// EntityBelief is a remembered entity as seen through Observation.Known
+// entities and belief signals: its last sighting, aged, and where it is
+// likely to be now.
+type EntityBelief struct {
+	ID       string
+	LastSeen core.Position
+	Likely   core.Position
+	Heading  core.Position
+	Age      int
+	Rumor    bool
+	Stale    bool
+}
+
+// rememberEntities records the entities the snapshot reports as visible.
+// A sighting refreshed on consecutive ticks learns the entity's heading.
+// Sightings whose likely position is in view but empty turn stale.
+func (m *Memory) rememberEntities(obs interface{}, tick int, visible []core.TileView) {
+	ev, ok := obs.(interface{ EntitiesValue() []core.EntityView })
+	if !ok {
+		return
+	}
+	for _, e := range ev.EntitiesValue() {
+		old, seen := m.entities[e.ID]
+		if seen && old.LastSeen == tick && !old.Rumor {
+			continue
+		}
+		em := EntityMemory{ID: e.ID, Position: e.Position, LastSeen: tick}
+		if seen && !old.Rumor && old.LastSeen == tick-1 {
+			em.Heading = unitStep(old.Position, e.Position)
+		}
+		m.setEntity(em)
+	}
+
+	inView := make(map[core.Position]struct{}, len(visible))
+	for _, tv := range visible {
+		inView[tv.Position] = struct{}{}
+	}
+	for _, em := range m.Entities() {
+		if em.Stale || em.LastSeen == tick {
+			continue
+		}
+		if _, ok := inView[em.Likely(tick)]; ok {
+			em.Stale = true
+			m.setEntity(em)
+		}
+	}
+}
+
+// unitStep returns the direction from a to b with each axis clamped to -1..1.
+func unitStep(a, b core.Position) core.Position {
+	return core.Position{X: sign(b.X - a.X), Y: sign(b.Y - a.Y)}
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// setEntity stores a sighting and marks the memory dirty. All changes to
+// entity sightings go through setEntity.
+func (m *Memory) setEntity(em EntityMemory) {
+	if m.entities == nil {
+		m.entities = make(map[string]EntityMemory)
+	}
+	m.entities[em.ID] = em
+	m.dirty = true
+}
+
+// SetEntityMemory inserts or replaces the sighting of em.ID.
+func (m *Memory) SetEntityMemory(em EntityMemory) {
+	if m != nil {
+		m.setEntity(em)
+	}
+}
+
+// EntityMemory returns the sighting of entity id and whether there is one.
+func (m *Memory) EntityMemory(id string) (EntityMemory, bool) {
+	if m == nil {
+		return EntityMemory{}, false
+	}
+	em, ok := m.entities[id]
+	return em, ok
+}
+
+// Entities returns every remembered sighting, ordered by entity id.
+func (m *Memory) Entities() []EntityMemory {
+	if m == nil {
+		return nil
+	}
+	out := make([]EntityMemory, 0, len(m.entities))
+	for _, em := range m.entities {
+		out = append(out, em)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// EntityBeliefs returns the remembered entities as beliefs aged to tick,
+// ordered by entity id.
+func (m *Memory) EntityBeliefs(tick int) []EntityBelief {
+	ems := m.Entities()
+	out := make([]EntityBelief, 0, len(ems))
+	for _, em := range ems {
+		out = append(out, EntityBelief{
+			ID:       em.ID,
+			LastSeen: em.Position,
+			Likely:   em.Likely(tick),
+			Heading:  em.Heading,
+			Age:      tick - em.LastSeen,
+			Rumor:    em.Rumor,
+			Stale:    em.Stale,
+		})
+	}
+	return out
+}
+
+// applyEntityContagion adopts the entity sightings nearby agents emitted
+// this tick. The receiver takes a sighting only when it has none of that
+// entity or an older one, weakened by its TransferPenalty and marked as a
+// rumour; nobody adopts a sighting of itself or one the sender knows is
+// stale. It returns the number of sightings adopted.
+func applyEntityContagion(receiverID string, receiverPos core.Position, tick int, receiverMem *Memory, p CognitionProfile) int {
+	if receiverMem == nil {
+		return 0
+	}
+	adopted := 0
+	for _, senderID := range signalSenders() {
+		sig := beliefSignals[senderID]
+		if senderID == receiverID || manhattan(sig.Position, receiverPos) > p.BeliefRadius {
+			continue
+		}
+		for _, eb := range sig.Entities {
+			if eb.ID == receiverID || eb.Stale {
+				continue
+			}
+			heard := tick - eb.Age - p.TransferPenalty
+			if cur, ok := receiverMem.entities[eb.ID]; ok && cur.LastSeen >= heard {
+				continue
+			}
+			receiverMem.setEntity(EntityMemory{ID: eb.ID, Position: eb.LastSeen, LastSeen: heard, Heading: eb.Heading, Rumor: true})
+			adopted++
+		}
+	}
+	return adopted
+}
@@ -0,0 +1,155 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+// sightingSnap is a snapshot that reports visible entities.
+type sightingSnap struct {
+	fakeSnapPos
+	entities []core.EntityView
+}
+
+func (s sightingSnap) EntitiesValue() []core.EntityView { return s.entities }
+
+func sees(tick int, es ...core.EntityView) sightingSnap {
+	return sightingSnap{fakeSnapPos{tick: tick}, es}
+}
+
+func TestMemory_LearnsHeadingAndInfersPosition(t *testing.T) {
+	m := NewMemory()
+	m.UpdateFromVisible(sees(4, core.EntityView{ID: "W", Position: core.Position{X: 5, Y: 5}}))
+	m.UpdateFromVisible(sees(5, core.EntityView{ID: "W", Position: core.Position{X: 6, Y: 5}}))
+	em, ok := m.EntityMemory("W")
+	if !ok || em.LastSeen != 5 || em.Heading != (core.Position{X: 1}) || em.Rumor {
+		t.Fatalf("sighting = %+v, %v", em, ok)
+	}
+	if got := em.Likely(7); got != (core.Position{X: 8, Y: 5}) {
+		t.Fatalf("Likely(7) = %v", got)
+	}
+	if got := em.Likely(50); got != (core.Position{X: 6 + inferHorizon, Y: 5}) {
+		t.Fatalf("Likely beyond the horizon = %v", got)
+	}
+
+	// A gap between sightings says nothing about heading.
+	m.UpdateFromVisible(sees(9, core.EntityView{ID: "W", Position: core.Position{X: 2, Y: 2}}))
+	if em, _ := m.EntityMemory("W"); em.Heading != (core.Position{}) {
+		t.Fatalf("heading after a gap = %v", em.Heading)
+	}
+}
+
+func TestEntityContagion_AdoptsRumoursOfOthers(t *testing.T) {
+	beliefSignals = map[string]BeliefSignal{}
+	tick := 20
+	emitBeliefSignal("A", tick, core.Position{X: 0, Y: 0}, nil, []EntityBelief{
+		{ID: "W", LastSeen: core.Position{X: 9, Y: 9}, Age: 1},
+		{ID: "B", LastSeen: core.Position{X: 1, Y: 0}, Age: 0},
+		{ID: "K", LastSeen: core.Position{X: 3, Y: 3}, Age: 5},
+	})
+	mem := NewMemory()
+	mem.SetEntityMemory(EntityMemory{ID: "K", Position: core.Position{X: 4, Y: 4}, LastSeen: 18})
+
+	if n := applyEntityContagion("B", core.Position{X: 1, Y: 0}, tick, mem, DefaultProfile()); n != 1 {
+		t.Fatalf("adopted %d sightings, want 1", n)
+	}
+	w, ok := mem.EntityMemory("W")
+	if !ok || !w.Rumor || w.LastSeen != tick-1-TransferPenalty || w.Position != (core.Position{X: 9, Y: 9}) {
+		t.Fatalf("rumour of W = %+v, %v", w, ok)
+	}
+	if _, ok := mem.EntityMemory("B"); ok {
+		t.Fatal("receiver adopted a sighting of itself")
+	}
+	if k, _ := mem.EntityMemory("K"); k.Rumor || k.LastSeen != 18 {
+		t.Fatalf("older rumour replaced a fresher sighting: %+v", k)
+	}
+}
+
+func TestObservation_HallucinatesStaleSightings(t *testing.T) {
+	mem := NewMemory()
+	mem.SetEntityMemory(EntityMemory{ID: "W", Position: core.Position{X: 3, Y: 0}, LastSeen: 0, Heading: core.Position{X: 1}})
+	mem.SetEntityMemory(EntityMemory{ID: "V", Position: core.Position{X: 0, Y: 3}, LastSeen: 0})
+	tick := ParanoiaThreshold + 1
+	snap := sees(tick, core.EntityView{ID: "V", Position: core.Position{X: 0, Y: 2}})
+
+	obs := buildObservation(mem, snap, nil, MaxEnergy, ParanoiaThreshold)
+	if obs.Hallucinated != 1 || len(obs.Entities) != 2 {
+		t.Fatalf("hallucinated %d, entities %+v", obs.Hallucinated, obs.Entities)
+	}
+	phantom := obs.Entities[1]
+	if phantom.ID != "W" || phantom.Position != (core.Position{X: 3 + inferHorizon, Y: 0}) {
+		t.Fatalf("phantom = %+v", phantom)
+	}
+	if len(obs.KnownEntities) != 2 || obs.KnownEntities[0].ID != "V" {
+		t.Fatalf("known entities = %+v", obs.KnownEntities)
+	}
+
+	fresh := buildObservation(mem, sees(ParanoiaThreshold), nil, MaxEnergy, ParanoiaThreshold)
+	if fresh.Hallucinated != 0 || len(fresh.Entities) != 0 {
+		t.Fatalf("sightings within the threshold hallucinated: %+v", fresh.Entities)
+	}
+}
+
+func TestDescribe_SomeoneWasHere(t *testing.T) {
+	here := core.Position{X: 1, Y: 0}
+	ob := Observation{
+		Visible:       []core.TileView{{Position: here}},
+		KnownEntities: []EntityBelief{{ID: "W", LastSeen: here, Age: 2}},
+	}
+	lines := Describe(ob, ReadOnlyAgentState{Energy: MaxEnergy, EffectiveParanoia: ParanoiaThreshold, EffectiveCaution: CautionThreshold})
+	if len(lines) != 1 || lines[0] != "Someone was here." {
+		t.Fatalf("narration = %q", lines)
+	}
+	ob.KnownEntities[0].Age = 0
+	if lines := Describe(ob, ReadOnlyAgentState{Energy: MaxEnergy, EffectiveParanoia: ParanoiaThreshold}); lines[0] == "Someone was here." {
+		t.Fatal("narrated an entity that is still in view")
+	}
+}
+
+// viewSnap reports visible tiles as well as entities.
+type viewSnap struct {
+	sightingSnap
+	tiles []core.TileView
+}
+
+func (s viewSnap) VisibleTiles() []core.TileView { return s.tiles }
+
+func TestMemory_SightingTurnsStaleWhenItsSpotIsEmpty(t *testing.T) {
+	spot := core.Position{X: 2, Y: 0}
+	m := NewMemory()
+	m.UpdateFromVisible(sees(0, core.EntityView{ID: "K", Position: spot}))
+
+	// Out of view the sighting ages into a hallucination.
+	tick := ParanoiaThreshold + 10
+	if obs := buildObservation(m, sees(tick), nil, MaxEnergy, ParanoiaThreshold); obs.Hallucinated != 1 {
+		t.Fatalf("old sighting out of view: hallucinated %d", obs.Hallucinated)
+	}
+
+	// Walking past the empty spot refutes it.
+	empty := viewSnap{sees(tick), []core.TileView{{Position: spot, Glyph: '.', Visible: true}}}
+	m.UpdateFromVisible(empty)
+	em, ok := m.EntityMemory("K")
+	if !ok || !em.Stale || em.LastSeen != 0 {
+		t.Fatalf("refuted sighting = %+v, %v", em, ok)
+	}
+	obs := buildObservation(m, sees(tick+1), nil, MaxEnergy, ParanoiaThreshold)
+	if obs.Hallucinated != 0 || len(obs.Entities) != 0 {
+		t.Fatalf("stale sighting hallucinated: %+v", obs.Entities)
+	}
+	if lines := Describe(Observation{Visible: empty.tiles, KnownEntities: m.EntityBeliefs(tick)}, ReadOnlyAgentState{Energy: MaxEnergy, EffectiveParanoia: ParanoiaThreshold}); lines[0] != "Someone was here." {
+		t.Fatalf("narration = %q", lines)
+	}
+
+	beliefSignals = map[string]BeliefSignal{}
+	emitBeliefSignal("A", tick, core.Position{}, nil, m.EntityBeliefs(tick))
+	if n := applyEntityContagion("B", core.Position{}, tick, NewMemory(), DefaultProfile()); n != 0 {
+		t.Fatalf("stale sighting was shared %d times", n)
+	}
+
+	// Seeing the entity again makes the sighting current.
+	m.UpdateFromVisible(sees(tick+2, core.EntityView{ID: "K", Position: spot}))
+	if em, _ := m.EntityMemory("K"); em.Stale || em.LastSeen != tick+2 {
+		t.Fatalf("sighting after seeing K again = %+v", em)
+	}
+}
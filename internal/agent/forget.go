@@ -0,0 +1,148 @@
+package agent
+
+import (
+	"sort"
+
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/util"
+)
+
+// Forgetting. Memory loses beliefs, tiles and entity sightings alike, in
+// two ways, both set by the agent's CognitionProfile and both off in
+// DefaultProfile:
+//
+//   - decay: every belief older than DecayAge has a DecayChance percent
+//     chance of fading each decision;
+//   - capacity: when more than Capacity beliefs are held, the least
+//     memorable are evicted until the memory fits.
+//
+// Beliefs refreshed this tick are never forgotten, so a memory may briefly
+// hold more than Capacity beliefs when the agent can see that many tiles.
+// Randomness comes from the snapshot's seed, which the runtime derives from
+// the run seed, so a run forgets the same beliefs every time it is replayed.
+
+// salientBonus is how much older an ordinary belief may be than a salient
+// one (anything but open floor) before the salient one is evicted first.
+const salientBonus = 8
+
+// scarWeight is how much older each scar level makes a belief look to
+// eviction: scarred beliefs are unreliable and go early.
+const scarWeight = 2
+
+// forgetScore ranks beliefs for eviction; the highest goes first.
+func forgetScore(mt MemoryTile, tick int) int {
+	score := tick - mt.LastSeen + scarWeight*mt.ScarLevel
+	if g := mt.Tile.Glyph; g != 0 && g != '.' {
+		score -= salientBonus
+	}
+	return score
+}
+
+// entityForgetScore ranks entity sightings alongside tiles. Another entity
+// is salient until the agent has found it gone.
+func entityForgetScore(em EntityMemory, tick int) int {
+	score := tick - em.LastSeen
+	if !em.Stale {
+		score -= salientBonus
+	}
+	return score
+}
+
+// forgettable is a belief Forget may lose: a tile at pos, or the sighting
+// of entity id when id is set.
+type forgettable struct {
+	pos      core.Position
+	id       string
+	lastSeen int
+	score    int
+}
+
+// forgettables lists the beliefs not refreshed at tick: tiles in position
+// order, then sightings in id order, so the random draws land on the same
+// beliefs every run.
+func (m *Memory) forgettables(tick int) []forgettable {
+	out := make([]forgettable, 0, len(m.tiles)+len(m.entities))
+	for _, pos := range m.positions() {
+		if mt := m.tiles[pos]; mt.LastSeen < tick {
+			out = append(out, forgettable{pos: pos, lastSeen: mt.LastSeen, score: forgetScore(mt, tick)})
+		}
+	}
+	for _, em := range m.Entities() {
+		if em.LastSeen < tick {
+			out = append(out, forgettable{id: em.ID, lastSeen: em.LastSeen, score: entityForgetScore(em, tick)})
+		}
+	}
+	return out
+}
+
+// Forget applies the decay and capacity rules of p at tick, drawing from a
+// generator seeded with seed, and returns how many beliefs were lost.
+func (m *Memory) Forget(tick int, p CognitionProfile, seed uint64) int {
+	if m == nil || (p.Capacity <= 0 && p.DecayAge <= 0) {
+		m.recordForgotten(0)
+		return 0
+	}
+	beliefs := m.forgettables(tick)
+
+	lost := 0
+	if p.DecayAge > 0 && p.DecayChance > 0 {
+		rng := util.NewRand(seed)
+		kept := beliefs[:0]
+		for _, b := range beliefs {
+			if tick-b.lastSeen > p.DecayAge && rng.Intn(100) < p.DecayChance {
+				m.drop(b)
+				lost++
+				continue
+			}
+			kept = append(kept, b)
+		}
+		beliefs = kept
+	}
+
+	if over := len(m.tiles) + len(m.entities) - p.Capacity; p.Capacity > 0 && over > 0 {
+		// Stable on the order above, so equal scores evict
+		// deterministically.
+		sort.SliceStable(beliefs, func(i, j int) bool {
+			return beliefs[i].score > beliefs[j].score
+		})
+		for i := 0; i < over && i < len(beliefs); i++ {
+			m.drop(beliefs[i])
+			lost++
+		}
+	}
+	m.recordForgotten(lost)
+	return lost
+}
+
+// drop forgets b and marks the memory dirty.
+func (m *Memory) drop(b forgettable) {
+	if b.id != "" {
+		delete(m.entities, b.id)
+	} else {
+		delete(m.tiles, b.pos)
+	}
+	m.dirty = true
+}
+
+func (m *Memory) recordForgotten(n int) {
+	if m != nil {
+		m.forgotten = n
+	}
+}
+
+// Forgotten returns how many beliefs the last call to Forget lost.
+func (m *Memory) Forgotten() int {
+	if m == nil {
+		return 0
+	}
+	return m.forgotten
+}
+
+// snapshotSeed returns the snapshot's forgetting seed, or 0 when the
+// snapshot carries none.
+func snapshotSeed(snapshot Snapshot) uint64 {
+	if s, ok := snapshot.(interface{ SeedValue() uint64 }); ok {
+		return s.SeedValue()
+	}
+	return 0
+}
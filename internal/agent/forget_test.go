@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+func rememberAt(m *Memory, x, y int, glyph rune, lastSeen, scar int) core.Position {
+	p := core.Position{X: x, Y: y}
+	m.SetMemoryTile(p, MemoryTile{Tile: core.TileView{Position: p, Glyph: glyph}, LastSeen: lastSeen, ScarLevel: scar})
+	return p
+}
+
+func TestForget_DefaultProfileKeepsEverything(t *testing.T) {
+	m := NewMemory()
+	for x := 0; x < 50; x++ {
+		rememberAt(m, x, 0, '.', 0, 0)
+	}
+	if n := m.Forget(1000, DefaultProfile(), 1); n != 0 || m.Count() != 50 {
+		t.Fatalf("default profile forgot %d, %d left", n, m.Count())
+	}
+}
+
+func TestForget_CapacityEvictsLeastMemorable(t *testing.T) {
+	tick := 20
+	m := NewMemory()
+	now := rememberAt(m, 0, 0, '.', tick, 0)       // seen this tick: never forgotten
+	floor := rememberAt(m, 1, 0, '.', tick-6, 0)   // score 6
+	wall := rememberAt(m, 2, 0, '#', tick-10, 0)   // score 10-8 = 2
+	scarred := rememberAt(m, 3, 0, '.', tick-2, 3) // score 2+6 = 8
+	fresh := rememberAt(m, 4, 0, '.', tick-1, 0)   // score 1
+
+	p := DefaultProfile()
+	p.Capacity = 3
+	if n := m.Forget(tick, p, 1); n != 2 {
+		t.Fatalf("forgot %d beliefs, want 2", n)
+	}
+	for _, pos := range []core.Position{scarred, floor} {
+		if _, ok := m.GetMemoryTile(pos); ok {
+			t.Errorf("belief at %v survived eviction", pos)
+		}
+	}
+	for _, pos := range []core.Position{now, wall, fresh} {
+		if _, ok := m.GetMemoryTile(pos); !ok {
+			t.Errorf("belief at %v was evicted", pos)
+		}
+	}
+	if m.Forgotten() != 2 || !m.Dirty() {
+		t.Fatalf("Forgotten() = %d, dirty = %v", m.Forgotten(), m.Dirty())
+	}
+}
+
+func TestForget_DecayIsSeededAndSparesRecentBeliefs(t *testing.T) {
+	build := func() *Memory {
+		m := NewMemory()
+		for x := 0; x < 40; x++ {
+			rememberAt(m, x, 0, '.', 0, 0)  // age 30
+			rememberAt(m, x, 1, '.', 28, 0) // age 2
+		}
+		return m
+	}
+	p := DefaultProfile()
+	p.DecayAge, p.DecayChance = 5, 50
+
+	a, b := build(), build()
+	na, nb := a.Forget(30, p, 42), b.Forget(30, p, 42)
+	if na != nb || na == 0 || na == 40 {
+		t.Fatalf("same seed forgot %d and %d of 40 old beliefs", na, nb)
+	}
+	for x := 0; x < 40; x++ {
+		_, okA := a.GetMemoryTile(core.Position{X: x, Y: 0})
+		_, okB := b.GetMemoryTile(core.Position{X: x, Y: 0})
+		if okA != okB {
+			t.Fatalf("same seed forgot different beliefs at x=%d", x)
+		}
+		if _, ok := a.GetMemoryTile(core.Position{X: x, Y: 1}); !ok {
+			t.Fatalf("belief younger than DecayAge decayed at x=%d", x)
+		}
+	}
+}
+
+func TestForget_ReportedByIntrospectionAndNarration(t *testing.T) {
+	s := NewScripted("F")
+	p := DefaultProfile()
+	p.DecayAge, p.DecayChance = 1, 100
+	s.SetProfile(p)
+	rememberAt(s.memory, 5, 5, '.', 0, 0)
+
+	s.Decide(fakeSnapPos{pos: core.Position{X: 0, Y: 0}, tick: 10})
+	if s.LastDecision().Forgotten != 1 || s.memory.Count() != 0 {
+		t.Fatalf("stats = %+v, %d beliefs left", s.LastDecision(), s.memory.Count())
+	}
+	if rpt := Introspect(*s.memory, 10); rpt.Forgotten != 1 {
+		t.Fatalf("introspection Forgotten = %d, want 1", rpt.Forgotten)
+	}
+	lines := Describe(Observation{}, ReadOnlyAgentState{Energy: MaxEnergy, Forgotten: 1})
+	if len(lines) != 1 || lines[0] != "The details are slipping away." {
+		t.Fatalf("narration = %q", lines)
+	}
+}
+
+func TestForget_CoversEntitySightings(t *testing.T) {
+	tick := 30
+	m := NewMemory()
+	wall := rememberAt(m, 0, 0, '#', tick-5, 0)                               // score -3
+	m.SetEntityMemory(EntityMemory{ID: "A", LastSeen: tick - 20})             // score 12
+	m.SetEntityMemory(EntityMemory{ID: "B", LastSeen: tick - 4, Stale: true}) // score 4
+	m.SetEntityMemory(EntityMemory{ID: "C", LastSeen: tick})                  // seen this tick
+
+	p := DefaultProfile()
+	p.Capacity = 2
+	if n := m.Forget(tick, p, 1); n != 2 {
+		t.Fatalf("forgot %d beliefs, want 2", n)
+	}
+	if _, ok := m.GetMemoryTile(wall); !ok {
+		t.Fatal("wall was evicted before older sightings")
+	}
+	if got := m.Entities(); len(got) != 1 || got[0].ID != "C" {
+		t.Fatalf("sightings left = %+v, want only C", got)
+	}
+
+	p = DefaultProfile()
+	p.DecayAge, p.DecayChance = 8, 100
+	m.SetEntityMemory(EntityMemory{ID: "D", LastSeen: tick - 10})
+	if n := m.Forget(tick+1, p, 1); n != 1 {
+		t.Fatalf("decay forgot %d beliefs, want 1", n)
+	}
+	if _, ok := m.EntityMemory("D"); ok {
+		t.Fatal("old sighting survived certain decay")
+	}
+}
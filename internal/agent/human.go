@@ -17,6 +17,11 @@ var HumanInput func() (string, error) = func() (string, error) {
 	return s, err
 }
 
+// defaultHumanRadius is drawn when a snapshot reports no radius. It is the
+// runtime's default visibility radius, kept as a literal so agent does not
+// import runtime.
+const defaultHumanRadius = 2
+
 type Human struct {
 	id     string
 	memory *Memory
@@ -25,20 +30,23 @@ type Human struct {
 	snaps        snapshotRing
 	inReplay     bool
 	replayCursor int // offset from newest (0=newest)
+
+	saved saveMark
+
+	// stats counts what happened in the last decision.
+	stats DecisionStats
+
+	profile CognitionProfile
 }
 
 func NewHuman(id string) *Human {
-	return &Human{id: id, memory: NewMemory(), energy: MaxEnergy}
+	return &Human{id: id, memory: NewMemory(), energy: MaxEnergy, profile: DefaultProfile()}
 }
 
 func (h *Human) ID() string      { return h.id }
 func (h *Human) Memory() *Memory { return h.memory }
 func (h *Human) Energy() int     { return h.energy }
 
-// visibility radius must match the runtime default (kept as literal to
-// avoid touching runtime package). This mirrors runtime.defaultVisibilityRadius.
-const humanVisibilityRadius = 2
-
 func keyToAction(key string) Action {
 	if key == "" {
 		return WAIT
@@ -91,33 +99,42 @@ func (h *Human) Decide(snapshot Snapshot) Action {
 	// Runtime will call EmitBeliefs for all agents before contagion; keep
 	// the in-Decision emit as a fallback for cases where EmitBeliefs isn't
 	// invoked (backwards compatibility).
-	emitBeliefSignal(h.id, tick, pos, beliefs)
+	emitBeliefSignal(h.id, tick, pos, beliefs, h.memory.EntityBeliefs(tick))
 
 	// 3. Apply contagion
-	_ = applyBeliefContagion(h.id, pos, tick, h.memory, h.energy)
+	transfers := applyBeliefContagion(h.id, pos, tick, h.memory, h.energy, h.profile)
+	rumours := applyEntityContagion(h.id, pos, tick, h.memory, h.profile)
 
 	// 4. Detect & apply conflicts
-	detectAndApplyConflicts(h.memory, prev, tick)
+	scars := detectAndApplyConflicts(h.memory, prev, tick, h.profile)
+
+	// Let go of what the profile cannot hold on to.
+	forgotten := h.memory.Forget(tick, h.profile, snapshotSeed(snapshot))
 
 	// 5. Build Observation
-	effectiveParanoia := ParanoiaThreshold
-	effectiveCaution := CautionThreshold
-	if h.energy < LowEnergyThreshold {
-		effectiveParanoia = ParanoiaThreshold - 2
-		effectiveCaution = CautionThreshold - 1
-	}
+	effectiveParanoia, effectiveCaution := h.profile.thresholds(h.energy)
 	obs := buildObservation(h.memory, snapshot, prev, h.energy, effectiveParanoia)
+	h.stats = DecisionStats{Transfers: len(transfers) + rumours, Scars: scars, Hallucinations: obs.Hallucinated, Forgotten: forgotten}
 
 	// 6. Render Observation to terminal (viewport centered on agent)
 	visMap := map[core.Position]rune{}
 	for _, v := range obs.Visible {
 		visMap[v.Position] = v.Glyph
 	}
+	others := map[core.Position]struct{}{}
+	for _, e := range obs.Entities {
+		others[e.Position] = struct{}{}
+	}
 	center := core.Position{X: 0, Y: 0}
 	if p, ok := snapshot.(interface{ PositionValue() core.Position }); ok {
 		center = p.PositionValue()
 	}
-	r := humanVisibilityRadius
+	// The runtime reports the effective radius with each snapshot; older
+	// snapshots without one get the runtime's old default.
+	r := obs.Radius
+	if r <= 0 {
+		r = defaultHumanRadius
+	}
 	for dy := -r; dy <= r; dy++ {
 		line := ""
 		for dx := -r; dx <= r; dx++ {
@@ -126,6 +143,10 @@ func (h *Human) Decide(snapshot Snapshot) Action {
 				line += "@"
 				continue
 			}
+			if _, ok := others[pos]; ok {
+				line += "&"
+				continue
+			}
 			if g, ok := visMap[pos]; ok {
 				if g == 0 {
 					line += "."
@@ -150,6 +171,7 @@ func (h *Human) Decide(snapshot Snapshot) Action {
 		EffectiveCaution:  effectiveCaution,
 		SumScars:          sumScars,
 		BeliefCount:       h.memory.Count(),
+		Forgotten:         forgotten,
 		Position:          center,
 		Tick:              obs.Tick,
 	}
@@ -191,6 +213,9 @@ func (h *Human) Decide(snapshot Snapshot) Action {
 			} else {
 				fmt.Println("\nYour thoughts feel settled.")
 			}
+			if rpt.Forgotten > 0 {
+				fmt.Println("The details are slipping away.")
+			}
 			// After rendering introspection, continue loop to read next input.
 			input = ""
 			continue
@@ -227,6 +252,9 @@ func (h *Human) Decide(snapshot Snapshot) Action {
 				} else {
 					fmt.Println("\nYour thoughts feel settled.")
 				}
+				if snap.Report.Forgotten > 0 {
+					fmt.Println("The details are slipping away.")
+				}
 			}
 			// continue reading input while in replay
 			input = ""
@@ -265,32 +293,11 @@ func (h *Human) Decide(snapshot Snapshot) Action {
 	}
 
 	// 12. Apply energy effects
-	switch final {
-	case MOVE_N, MOVE_S, MOVE_E, MOVE_W:
-		h.energy -= MoveEnergyCost
-	case OBSERVE:
-		h.energy -= ObserveEnergyCost
-	case WAIT:
-		h.energy += WaitEnergyRestore
-	}
-	if h.energy > MaxEnergy {
-		h.energy = MaxEnergy
-	}
-	if h.energy < MinEnergy {
-		h.energy = MinEnergy
-	}
+	h.energy = h.profile.spend(h.energy, final)
 
 	// 13. OBSERVE healing
-	if final == OBSERVE && h.memory != nil {
-		for pos, mt := range h.memory.tiles {
-			if mt.ScarLevel > 0 {
-				mt.ScarLevel -= 1
-				if mt.ScarLevel < 0 {
-					mt.ScarLevel = 0
-				}
-				h.memory.tiles[pos] = mt
-			}
-		}
+	if final == OBSERVE {
+		h.memory.healScars()
 	}
 
 	// Snapshot capture: append introspection snapshot after a real action resolves.
@@ -323,5 +330,5 @@ func (h *Human) EmitBeliefs(snapshot Snapshot) {
 	if t, ok := snapshot.(interface{ TickValue() int }); ok {
 		tick = t.TickValue()
 	}
-	emitBeliefSignal(h.id, tick, pos, beliefs)
+	emitBeliefSignal(h.id, tick, pos, beliefs, h.memory.EntityBeliefs(tick))
 }
@@ -1,6 +1,9 @@
 package agent
 
 import (
+	"io"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/divijg19/Nightshade/internal/core"
@@ -74,7 +77,7 @@ func TestHumanAffectedByBeliefContagion(t *testing.T) {
 	tick := 50
 	senderPos := core.Position{X:0, Y:0}
 	tilePos := core.Position{X:1, Y:0}
-	emitBeliefSignal("S", tick, senderPos, []Belief{{Tile: core.TileView{Position: tilePos}, Age: 0}})
+	emitBeliefSignal("S", tick, senderPos, []Belief{{Tile: core.TileView{Position: tilePos}, Age: 0}}, nil)
 
 	// human positioned at (0,0) should receive contagion when Decide runs
 	h := NewHuman("H4")
@@ -89,3 +92,64 @@ func TestHumanAffectedByBeliefContagion(t *testing.T) {
 		t.Fatalf("expected contagion to transfer belief into human memory")
 	}
 }
+
+type snapWithRadius struct {
+	fakeSnap
+	radius int
+}
+
+func (s snapWithRadius) VisibilityRadiusValue() int { return s.radius }
+
+func TestBuildObservation_CarriesRadius(t *testing.T) {
+	snap := snapWithRadius{fakeSnap: fakeSnap{tick: 3}, radius: 4}
+	obs := buildObservation(NewMemory(), snap, nil, MaxEnergy, ParanoiaThreshold)
+	if obs.Radius != 4 {
+		t.Fatalf("observation radius = %d, want 4", obs.Radius)
+	}
+}
+
+type snapWithEntities struct {
+	fakeSnap
+	entities []core.EntityView
+}
+
+func (s snapWithEntities) EntitiesValue() []core.EntityView { return s.entities }
+
+func TestBuildObservation_CarriesEntities(t *testing.T) {
+	seen := []core.EntityView{{ID: "B", Position: core.Position{X: 1, Y: 0}}}
+	snap := snapWithEntities{fakeSnap: fakeSnap{tick: 1}, entities: seen}
+	obs := buildObservation(NewMemory(), snap, nil, MaxEnergy, ParanoiaThreshold)
+	if len(obs.Entities) != 1 || obs.Entities[0].ID != "B" {
+		t.Fatalf("observation entities = %+v, want %+v", obs.Entities, seen)
+	}
+}
+
+func TestHumanDrawsDefaultRadiusWithoutOne(t *testing.T) {
+	h := NewHuman("H5")
+	HumanInput = makeInput(".")
+	defer func() { HumanInput = nil }()
+	out := captureStdout(t, func() { h.Decide(fakeSnap{tick: 1}) })
+	if !strings.Contains("\n"+out, "\n??@??\n") {
+		t.Fatalf("viewport without a reported radius:\n%s", out)
+	}
+}
+
+// captureStdout returns what fn prints to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	fn()
+	os.Stdout = orig
+	w.Close()
+	return <-done
+}
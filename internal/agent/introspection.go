@@ -9,17 +9,19 @@ type IntrospectionReport struct {
 	Fading         int
 	Doubtful       int
 	HasScars       bool
+	// Forgotten is how many beliefs slipped away in the last decision.
+	Forgotten      int
 }
 
 // Introspect analyzes a Memory and currentTick and returns a report of
 // belief bucket counts. This function is pure and performs no mutations.
 func Introspect(memory Memory, currentTick int) IntrospectionReport {
-	r := IntrospectionReport{}
+	r := IntrospectionReport{Forgotten: memory.forgotten}
 	// Defensive: handle nil memory
 	if memory.tiles == nil {
 		return r
 	}
-	for _, mt := range memory.tiles {
+	for _, mt := range memory.All() {
 		r.TotalBeliefs++
 		age := currentTick - mt.LastSeen
 		if age == 0 {
@@ -13,10 +13,12 @@ type snapshotRing struct {
 	buf   [32]IntrospectionSnapshot
 	count int // number of valid entries (<= len(buf))
 	head  int // index of newest entry in buf when count>0
+	total int // number of appends ever made; used for dirty tracking
 }
 
 // append a snapshot to the ring buffer (overwrites oldest if full)
 func (r *snapshotRing) append(s IntrospectionSnapshot) {
+	r.total++
 	if r.count == 0 {
 		r.head = 0
 		r.buf[0] = s
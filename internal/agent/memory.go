@@ -1,6 +1,10 @@
 package agent
 
-import "github.com/divijg19/Nightshade/internal/core"
+import (
+	"sort"
+
+	"github.com/divijg19/Nightshade/internal/core"
+)
 
 // MemoryTile represents a remembered tile and the tick when it was last
 // observed. This type lives in the agent layer and imports only internal/core.
@@ -11,24 +15,35 @@ type MemoryTile struct {
 }
 
 // Memory stores last-known tiles keyed by position. Memory imports only
-// internal/core and does not reference runtime.
+// internal/core and does not reference runtime. Everything that walks the
+// tiles does so in row-major position order, so observations, belief
+// signals and saved memory come out the same on every run.
 type Memory struct {
 	tiles map[core.Position]MemoryTile
+
+	// entities holds the last sighting of each other entity, by id.
+	entities map[string]EntityMemory
+
+	// dirty is set by every change and cleared by MarkClean, so persistence
+	// can skip memories that have not changed since they were last saved.
+	dirty bool
+
+	// forgotten is how many beliefs the last Forget lost.
+	forgotten int
 }
 
 // NewMemory constructs an empty Memory.
 func NewMemory() *Memory {
-	return &Memory{tiles: make(map[core.Position]MemoryTile)}
+	return &Memory{tiles: make(map[core.Position]MemoryTile), entities: make(map[string]EntityMemory)}
 }
 
-// UpdateFromVisible accepts an opaque observation and updates memory only
-// from what the runtime reports as currently visible. This uses a capability
-// interface rather than depending on runtime concrete types. When a tile is
-// visible we overwrite the stored Tile and set LastSeen = snapshot.TickValue().
 // UpdateFromVisible updates memory only from what the runtime reports as
-// currently visible. It returns a map of positions to their previous
-// LastSeen value (or -1 if not previously present) so callers can decide
-// how to treat recently-updated entries (useful for cognitive effects).
+// currently visible, through a capability interface rather than runtime
+// concrete types. Each visible tile overwrites the stored Tile with LastSeen
+// set to snapshot.TickValue(), and visible entities are remembered the same
+// way; see EntityMemory. It returns the previous entry for every visible
+// position (LastSeen -1 if there was none) so callers can decide how to
+// treat recently-updated entries (useful for cognitive effects).
 func (m *Memory) UpdateFromVisible(obs interface{}) map[core.Position]MemoryTile {
 	prev := make(map[core.Position]MemoryTile)
 	if m == nil {
@@ -40,30 +55,67 @@ func (m *Memory) UpdateFromVisible(obs interface{}) map[core.Position]MemoryTile
 	}
 	if v, ok := obs.(visTicker); ok {
 		tick := v.TickValue()
-		for _, tv := range v.VisibleTiles() {
+		visible := v.VisibleTiles()
+		for _, tv := range visible {
 			if old, ok := m.tiles[tv.Position]; ok {
 				prev[tv.Position] = old
 			} else {
 				prev[tv.Position] = MemoryTile{LastSeen: -1}
 			}
-			m.tiles[tv.Position] = MemoryTile{Tile: tv, LastSeen: tick}
+			m.set(tv.Position, MemoryTile{Tile: tv, LastSeen: tick})
 		}
+		m.rememberEntities(obs, tick, visible)
 	}
 	return prev
 }
 
-// All returns all MemoryTile entries currently remembered in memory.
+// All returns all MemoryTile entries currently remembered in memory, in
+// row-major position order.
 func (m *Memory) All() []MemoryTile {
 	if m == nil {
 		return nil
 	}
 	out := make([]MemoryTile, 0, len(m.tiles))
-	for _, mt := range m.tiles {
-		out = append(out, mt)
+	for _, pos := range m.positions() {
+		out = append(out, m.tiles[pos])
 	}
 	return out
 }
 
+// positions returns the remembered positions in row-major order.
+func (m *Memory) positions() []core.Position {
+	out := make([]core.Position, 0, len(m.tiles))
+	for pos := range m.tiles {
+		out = append(out, pos)
+	}
+	sortPositions(out)
+	return out
+}
+
+// sortPositions orders ps row-major: by Y, then X.
+func sortPositions(ps []core.Position) {
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Y != ps[j].Y {
+			return ps[i].Y < ps[j].Y
+		}
+		return ps[i].X < ps[j].X
+	})
+}
+
+// healScars lowers every scar by one level; OBSERVE heals a little each
+// tick it is performed.
+func (m *Memory) healScars() {
+	if m == nil {
+		return
+	}
+	for _, pos := range m.positions() {
+		if mt := m.tiles[pos]; mt.ScarLevel > 0 {
+			mt.ScarLevel--
+			m.set(pos, mt)
+		}
+	}
+}
+
 // Count returns the number of known tiles in memory.
 func (m *Memory) Count() int {
 	if m == nil {
@@ -95,7 +147,8 @@ func (m *Memory) GetMemoryTile(pos core.Position) (MemoryTile, bool) {
 }
 
 // SetMemoryTile inserts or replaces a MemoryTile at position `pos`.
-// This is used by server-side rehydration code to restore persisted memory.
+// Whole memories are restored with NewMemoryFrom or the encodings in
+// encoding.go.
 func (m *Memory) SetMemoryTile(pos core.Position, mt MemoryTile) {
 	if m == nil {
 		return
@@ -103,7 +156,26 @@ func (m *Memory) SetMemoryTile(pos core.Position, mt MemoryTile) {
 	if m.tiles == nil {
 		m.tiles = make(map[core.Position]MemoryTile)
 	}
+	m.set(pos, mt)
+}
+
+// set stores mt at pos and marks the memory dirty. All changes to tiles go
+// through set.
+func (m *Memory) set(pos core.Position, mt MemoryTile) {
 	m.tiles[pos] = mt
+	m.dirty = true
+}
+
+// Dirty reports whether memory changed since the last MarkClean.
+func (m *Memory) Dirty() bool {
+	return m != nil && m.dirty
+}
+
+// MarkClean records that the current contents have been saved.
+func (m *Memory) MarkClean() {
+	if m != nil {
+		m.dirty = false
+	}
 }
 
 // ReplaceAll replaces the backing tile map with the provided one. Useful
@@ -113,4 +185,5 @@ func (m *Memory) ReplaceAll(tiles map[core.Position]MemoryTile) {
 		return
 	}
 	m.tiles = tiles
+	m.dirty = true
 }
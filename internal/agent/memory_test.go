@@ -43,3 +43,44 @@ func TestMemoryLastSeenUpdates(t *testing.T) {
 		t.Fatalf("expected Tile to be overwritten on re-observation")
 	}
 }
+
+func TestMemoryAll_IsRowMajorWhateverTheInsertionOrder(t *testing.T) {
+	ps := []core.Position{{X: 3, Y: 1}, {X: 0, Y: 2}, {X: 7, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 0}}
+	want := []core.Position{{X: 2, Y: 0}, {X: 7, Y: 0}, {X: 1, Y: 1}, {X: 3, Y: 1}, {X: 0, Y: 2}}
+	for round := 0; round < 10; round++ {
+		mem := NewMemory()
+		for i := range ps {
+			p := ps[(i+round)%len(ps)]
+			mem.SetMemoryTile(p, MemoryTile{Tile: core.TileView{Position: p}, LastSeen: i})
+		}
+		for i, mt := range mem.All() {
+			if mt.Tile.Position != want[i] {
+				t.Fatalf("round %d: All()[%d] at %v, want %v", round, i, mt.Tile.Position, want[i])
+			}
+		}
+		obs := buildObservation(mem, fakeSnap{tick: 20}, nil, MaxEnergy, ParanoiaThreshold)
+		for i, k := range obs.Known {
+			if k.Tile.Position != want[i] {
+				t.Fatalf("round %d: Known[%d] at %v, want %v", round, i, k.Tile.Position, want[i])
+			}
+		}
+	}
+}
+
+func TestBeliefContagion_SendersVisitedInIDOrder(t *testing.T) {
+	target := core.Position{X: 4, Y: 4}
+	for round := 0; round < 20; round++ {
+		beliefSignals = map[string]BeliefSignal{}
+		beliefSignalsTick = -1
+		// Equally fresh, contradictory reports from either side. The first
+		// sender by id is adopted, weakened by TransferPenalty, so the second
+		// overrides it.
+		emitBeliefSignal("Z", 10, core.Position{X: 2, Y: 0}, []Belief{{Tile: core.TileView{Position: target, Glyph: 'z'}}}, nil)
+		emitBeliefSignal("M", 10, core.Position{X: 0, Y: 0}, []Belief{{Tile: core.TileView{Position: target, Glyph: 'm'}}}, nil)
+		mem := NewMemory()
+		applyBeliefContagion("R", core.Position{X: 1, Y: 0}, 10, mem, MaxEnergy, DefaultProfile())
+		if tv, _ := mem.Get(target); tv.Glyph != 'z' {
+			t.Fatalf("round %d: adopted %q, want the report of Z", round, tv.Glyph)
+		}
+	}
+}
@@ -19,4 +19,24 @@ type Observation struct {
 	Visible []core.TileView
 	Known   []Belief
 	Tick    int
+
+	// Radius is the effective visibility radius reported by the runtime for
+	// this tick, or 0 when the snapshot does not report one.
+	Radius int
+
+	// Entities lists the other entities the runtime reports as visible this
+	// tick, plus any remembered entity the agent hallucinates back into
+	// view at its likely position.
+	Entities []core.EntityView
+
+	// KnownEntities holds the agent's sightings of other entities, ordered
+	// by id, built from Memory like Known.
+	KnownEntities []EntityBelief
+
+	// Sounds are the noise cues the runtime reports this tick.
+	Sounds []core.SoundCue
+
+	// Hallucinated counts the remembered tiles injected into Visible and
+	// the remembered entities injected into Entities.
+	Hallucinated int
 }
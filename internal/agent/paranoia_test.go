@@ -40,6 +40,26 @@ func TestParanoiaInjectsHallucination(t *testing.T) {
     if !found {
         t.Fatalf("expected hallucinated tile at %v to be in Visible, but it was not", target)
     }
+    if obs.Hallucinated != 1 {
+        t.Fatalf("expected Hallucinated = 1, got %d", obs.Hallucinated)
+    }
+}
+
+// Test that agents report how many hallucinated tiles their last decision
+// saw, so spectators can tell when an agent is seeing things.
+func TestScriptedReportsHallucinations(t *testing.T) {
+    s := NewScripted("P3")
+    tick := 100
+    for _, p := range []core.Position{{X: 9, Y: 9}, {X: 10, Y: 9}} {
+        s.Memory().tiles[p] = MemoryTile{Tile: core.TileView{Position: p, Glyph: 'H'}, LastSeen: tick - (ParanoiaThreshold + 1)}
+    }
+    if s.Hallucinations() != 0 {
+        t.Fatalf("expected no hallucinations before deciding, got %d", s.Hallucinations())
+    }
+    _ = s.Decide(fakeSnapFull{tick: tick})
+    if s.Hallucinations() != 2 {
+        t.Fatalf("expected 2 hallucinations, got %d", s.Hallucinations())
+    }
 }
 
 // Test that when the runtime VisibleTiles contains a previously-hallucinated
@@ -7,14 +7,19 @@ import "github.com/divijg19/Nightshade/internal/core"
 // 1. Newly visible tiles: any Known belief with Age==0 -> "You notice something nearby."
 // 2. Hallucinated tile: Belief present in Observation.Visible but Age > effectiveParanoia -> "Something feels wrong here."
 // 3. Belief only: any Known belief with Age > effectiveParanoia -> "You think something might be there."
-// 4. Scar present: any Known.ScarLevel >= 1 -> "A familiar unease tightens."
-// 5. Low energy: Energy < LowEnergyThreshold -> "Your thoughts feel sluggish."
-// 6. Critical energy: Energy < CriticalEnergyThreshold -> "You can't trust your instincts right now."
-// 7. OBSERVE cue: if any movement target is stale (age > effectiveCaution) -> "You steady your breathing and focus."
+// 4. Departed entity: a KnownEntities sighting with Age > 0 on a tile now in view -> "Someone was here."
+// 5. Scar present: any Known.ScarLevel >= 1 -> "A familiar unease tightens."
+// 6. Forgetting: Forgotten > 0 -> "The details are slipping away."
+// 7. Low energy: Energy < LowEnergyThreshold -> "Your thoughts feel sluggish."
+// 8. Critical energy: Energy < CriticalEnergyThreshold -> "You can't trust your instincts right now."
+// 9. OBSERVE cue: if any movement target is stale (age > effectiveCaution) -> "You steady your breathing and focus."
+// 10. Sound cues: one line per distinct (kind, direction) in report order -> "Footsteps to the west."
+//    Cues with Loudness <= FaintLoudness are narrated as faint. Up to maxSoundLines
+//    of them always fit: the rules above are cut first to make room.
 // Notes:
 // - These mappings are deterministic and purely translational.
 // - No memory mutations or world changes are performed here.
-// - The function is pure and returns 1..7 short lines (max 7).
+// - The function is pure and returns 1..maxDescribeLines short lines.
 
 // ReadOnlyAgentState is a minimal read-only view passed to Describe.
 type ReadOnlyAgentState struct {
@@ -23,6 +28,8 @@ type ReadOnlyAgentState struct {
 	EffectiveCaution  int
 	SumScars         int
 	BeliefCount      int
+	// Forgotten is how many beliefs were lost this decision.
+	Forgotten        int
 	Position         core.Position
 	Tick             int
 }
@@ -61,7 +68,15 @@ func Describe(ob Observation, st ReadOnlyAgentState) []string {
 		}
 	}
 
-	// 4. Scar present
+	// 4. Departed entity: someone was seen on a tile that is in view now
+	for _, e := range ob.KnownEntities {
+		if _, ok := visMap[e.LastSeen]; ok && e.Age > 0 {
+			lines = append(lines, "Someone was here.")
+			break
+		}
+	}
+
+	// 5. Scar present
 	for _, k := range ob.Known {
 		if k.ScarLevel >= 1 {
 			lines = append(lines, "A familiar unease tightens.")
@@ -69,14 +84,19 @@ func Describe(ob Observation, st ReadOnlyAgentState) []string {
 		}
 	}
 
-	// 5/6. Energy cues
+	// 6. Forgetting
+	if st.Forgotten > 0 {
+		lines = append(lines, "The details are slipping away.")
+	}
+
+	// 7/8. Energy cues
 	if st.Energy < CriticalEnergyThreshold {
 		lines = append(lines, "You can't trust your instincts right now.")
 	} else if st.Energy < LowEnergyThreshold {
 		lines = append(lines, "Your thoughts feel sluggish.")
 	}
 
-	// 7. OBSERVE cue: if there exists a remembered movement target whose age > effectiveCaution
+	// 9. OBSERVE cue: if there exists a remembered movement target whose age > effectiveCaution
 	// We determine this by scanning Known beliefs for any position that would be a movement target
 	// relative to the agent position. This is an approximation used for deterministic narration
 	// without changing cognition. It does not alter agent decisions.
@@ -100,8 +120,58 @@ func Describe(ob Observation, st ReadOnlyAgentState) []string {
 		lines = append(lines, "You steady your breathing and focus.")
 	}
 
-	// Truncate to max 7 lines and return
-	if len(lines) > 7 { lines = lines[:7] }
+	// 10. Sound cues, at most maxSoundLines of them
+	heard := map[string]bool{}
+	sounds := []string{}
+	for _, c := range ob.Sounds {
+		line := soundLine(c)
+		if heard[line] {
+			continue
+		}
+		heard[line] = true
+		if len(sounds) < maxSoundLines {
+			sounds = append(sounds, line)
+		}
+	}
+
+	// Truncate the other rules to leave room for the sounds, then return
+	if room := maxDescribeLines - len(sounds); len(lines) > room { lines = lines[:room] }
+	lines = append(lines, sounds...)
 	if len(lines) == 0 { lines = append(lines, "You sense nothing unusual.") }
 	return lines
 }
+
+// maxDescribeLines is the most lines Describe returns.
+const maxDescribeLines = 7
+
+// maxSoundLines is how many of those lines are kept for sound cues, which
+// come last in the rule table and would otherwise be the first cut.
+const maxSoundLines = 2
+
+// FaintLoudness is the loudness at or below which a sound is narrated as
+// faint.
+const FaintLoudness = 2
+
+// soundLine narrates a single sound cue.
+func soundLine(c core.SoundCue) string {
+	faint := c.Loudness <= FaintLoudness
+	noun := "Something"
+	switch c.Kind {
+	case core.SoundFootsteps:
+		noun = "Footsteps"
+		if faint {
+			noun = "Faint footsteps"
+		}
+	case core.SoundStruggle:
+		noun = "A struggle"
+		if faint {
+			noun = "A faint struggle"
+		}
+	case core.SoundRustling:
+		noun = "Rustling"
+		if faint {
+			noun = "Faint rustling"
+		}
+	}
+	return noun + " to the " + c.Direction + "."
+}
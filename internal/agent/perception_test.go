@@ -64,3 +64,44 @@ func containsAny(lines []string, subs []string) bool {
 	}
 	return false
 }
+
+func TestDescribeSoundCues(t *testing.T) {
+	obs := Observation{Tick: 2, Sounds: []core.SoundCue{
+		{Kind: core.SoundFootsteps, Direction: "west", Loudness: 4},
+		{Kind: core.SoundFootsteps, Direction: "west", Loudness: 3},
+		{Kind: core.SoundStruggle, Direction: "northeast", Loudness: 1},
+	}}
+	st := ReadOnlyAgentState{Energy: MaxEnergy, EffectiveParanoia: ParanoiaThreshold, EffectiveCaution: CautionThreshold, Tick: 2}
+	lines := Describe(obs, st)
+	want := []string{"Footsteps to the west.", "A faint struggle to the northeast."}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Fatalf("Describe = %v, want %v", lines, want)
+	}
+}
+
+func TestDescribeSoundCues_SurviveTruncation(t *testing.T) {
+	here, east := core.Position{X: 0, Y: 0}, core.Position{X: 1, Y: 0}
+	obs := Observation{
+		Tick:    40,
+		Visible: []core.TileView{{Position: here}, {Position: east}},
+		Known: []Belief{
+			{Tile: core.TileView{Position: here}, Age: 0},
+			{Tile: core.TileView{Position: east}, Age: 30, ScarLevel: 1},
+		},
+		KnownEntities: []EntityBelief{{ID: "B", LastSeen: east, Age: 3}},
+		Sounds: []core.SoundCue{
+			{Kind: core.SoundFootsteps, Direction: "west", Loudness: 4},
+			{Kind: core.SoundRustling, Direction: "south", Loudness: 4},
+			{Kind: core.SoundStruggle, Direction: "north", Loudness: 4},
+		},
+	}
+	st := ReadOnlyAgentState{Energy: 1, EffectiveParanoia: ParanoiaThreshold, EffectiveCaution: CautionThreshold, Forgotten: 2, Position: here, Tick: 40}
+	lines := Describe(obs, st)
+	if len(lines) != maxDescribeLines {
+		t.Fatalf("Describe returned %d lines, want %d: %v", len(lines), maxDescribeLines, lines)
+	}
+	got := strings.Join(lines[len(lines)-2:], "|")
+	if want := "Footsteps to the west.|Rustling to the south."; got != want {
+		t.Fatalf("last lines = %q, want %q (all: %v)", got, want, lines)
+	}
+}
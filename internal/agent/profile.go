@@ -0,0 +1,260 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// CognitionProfile holds the parameters of an agent's psyche. Every agent
+// carries one; DefaultProfile reproduces the package constants, so agents
+// built without a profile behave as they always have.
+type CognitionProfile struct {
+	// Name labels the profile in files and debug output.
+	Name string `json:"name,omitempty"`
+
+	// Caution is how old a belief about a move target may be before the
+	// agent observes instead of moving. See CautionThreshold.
+	Caution int `json:"caution"`
+	// Paranoia is how old a belief may be before it is hallucinated as
+	// visible. See ParanoiaThreshold.
+	Paranoia int `json:"paranoia"`
+
+	MoveCost    int `json:"moveCost"`
+	ObserveCost int `json:"observeCost"`
+	WaitRestore int `json:"waitRestore"`
+
+	// BeliefRadius is how far away a sender may be for this agent to adopt
+	// its beliefs, and TransferPenalty how much older an adopted belief is
+	// made than when it was heard.
+	BeliefRadius    int `json:"beliefRadius"`
+	TransferPenalty int `json:"transferPenalty"`
+
+	// ConflictThreshold is the strength both sides of a contradiction need
+	// to scar the memory, and ScarPenalty how stale a scarred belief is
+	// made at least.
+	ConflictThreshold int `json:"conflictThreshold"`
+	ScarPenalty       int `json:"scarPenalty"`
+
+	// Capacity is the most beliefs, tiles and entity sightings together,
+	// memory holds before evicting; 0 means unlimited. Beliefs older than
+	// DecayAge fade with a DecayChance percent chance each decision; a
+	// DecayAge of 0 disables decay. See Memory.Forget.
+	Capacity    int `json:"capacity"`
+	DecayAge    int `json:"decayAge"`
+	DecayChance int `json:"decayChance"`
+}
+
+// DefaultProfile returns the profile described by the package constants.
+func DefaultProfile() CognitionProfile {
+	return CognitionProfile{
+		Name:              "default",
+		Caution:           CautionThreshold,
+		Paranoia:          ParanoiaThreshold,
+		MoveCost:          MoveEnergyCost,
+		ObserveCost:       ObserveEnergyCost,
+		WaitRestore:       WaitEnergyRestore,
+		BeliefRadius:      BeliefRadius,
+		TransferPenalty:   TransferPenalty,
+		ConflictThreshold: ConflictThreshold,
+		ScarPenalty:       ScarPenalty,
+	}
+}
+
+// Validate reports the first parameter that is out of range. Every
+// parameter must be non-negative, and DecayChance at most 100.
+func (p CognitionProfile) Validate() error {
+	fields := []struct {
+		name string
+		v    int
+	}{
+		{"caution", p.Caution},
+		{"paranoia", p.Paranoia},
+		{"moveCost", p.MoveCost},
+		{"observeCost", p.ObserveCost},
+		{"waitRestore", p.WaitRestore},
+		{"beliefRadius", p.BeliefRadius},
+		{"transferPenalty", p.TransferPenalty},
+		{"conflictThreshold", p.ConflictThreshold},
+		{"scarPenalty", p.ScarPenalty},
+		{"capacity", p.Capacity},
+		{"decayAge", p.DecayAge},
+		{"decayChance", p.DecayChance},
+	}
+	for _, f := range fields {
+		if f.v < 0 {
+			return fmt.Errorf("profile %q: %s is %d, want >= 0", p.Name, f.name, f.v)
+		}
+	}
+	if p.DecayChance > 100 {
+		return fmt.Errorf("profile %q: decayChance is %d, want a percentage", p.Name, p.DecayChance)
+	}
+	return nil
+}
+
+// thresholds returns the paranoia and caution thresholds in effect at the
+// given energy. Low energy makes every agent jumpier by the same amount.
+func (p CognitionProfile) thresholds(energy int) (paranoia, caution int) {
+	if energy < LowEnergyThreshold {
+		return p.Paranoia - 2, p.Caution - 1
+	}
+	return p.Paranoia, p.Caution
+}
+
+// spend returns energy after taking action a, clamped to the valid range.
+func (p CognitionProfile) spend(energy int, a Action) int {
+	switch a {
+	case MOVE_N, MOVE_S, MOVE_E, MOVE_W:
+		energy -= p.MoveCost
+	case OBSERVE:
+		energy -= p.ObserveCost
+	case WAIT:
+		energy += p.WaitRestore
+	}
+	if energy > MaxEnergy {
+		energy = MaxEnergy
+	}
+	if energy < MinEnergy {
+		energy = MinEnergy
+	}
+	return energy
+}
+
+// Profiled is implemented by agents whose cognition can be tuned.
+type Profiled interface {
+	Profile() CognitionProfile
+	SetProfile(p CognitionProfile)
+}
+
+// Profile returns the agent's cognition profile.
+func (h *Human) Profile() CognitionProfile { return h.profile }
+
+// SetProfile replaces the agent's cognition profile.
+func (h *Human) SetProfile(p CognitionProfile) { h.profile = p }
+
+// Profile returns the agent's cognition profile.
+func (r *RemoteHuman) Profile() CognitionProfile { return r.profile }
+
+// SetProfile replaces the agent's cognition profile.
+func (r *RemoteHuman) SetProfile(p CognitionProfile) { r.profile = p }
+
+// Profile returns the agent's cognition profile.
+func (s *Scripted) Profile() CognitionProfile { return s.profile }
+
+// SetProfile replaces the agent's cognition profile.
+func (s *Scripted) SetProfile(p CognitionProfile) { s.profile = p }
+
+// Profile returns the agent's cognition profile.
+func (o *Oscillating) Profile() CognitionProfile { return o.profile }
+
+// SetProfile replaces the agent's cognition profile.
+func (o *Oscillating) SetProfile(p CognitionProfile) { o.profile = p }
+
+// Profiles assigns cognition profiles to agents. A profiles file is JSON:
+//
+//	{
+//	  "profiles": {
+//	    "bold":     {"caution": 8, "paranoia": 12},
+//	    "paranoid": {"paranoia": 2, "conflictThreshold": 1}
+//	  },
+//	  "agents":  {"npc-scripted-1": "paranoid"},
+//	  "default": "bold"
+//	}
+//
+// Parameters a profile leaves out keep their DefaultProfile values. Agents
+// not listed under "agents" get the "default" profile, or DefaultProfile
+// when there is none.
+type Profiles struct {
+	byName   map[string]CognitionProfile
+	agents   map[string]string
+	fallback string
+}
+
+// LoadProfiles reads a profiles file.
+func LoadProfiles(path string) (*Profiles, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseProfiles(f, path)
+}
+
+// ParseProfiles parses a profiles file from r. file is used only in error
+// messages.
+func ParseProfiles(r io.Reader, file string) (*Profiles, error) {
+	var doc struct {
+		Profiles map[string]json.RawMessage `json:"profiles"`
+		Agents   map[string]string          `json:"agents"`
+		Default  string                     `json:"default"`
+	}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	ps := &Profiles{byName: map[string]CognitionProfile{}, agents: doc.Agents, fallback: doc.Default}
+	// Sorted so a file with several mistakes always reports the same one.
+	for _, name := range sortedKeys(doc.Profiles) {
+		p := DefaultProfile()
+		pd := json.NewDecoder(bytes.NewReader(doc.Profiles[name]))
+		pd.DisallowUnknownFields()
+		if err := pd.Decode(&p); err != nil {
+			return nil, fmt.Errorf("%s: profile %q: %w", file, name, err)
+		}
+		p.Name = name
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		ps.byName[name] = p
+	}
+	if ps.fallback != "" {
+		if _, ok := ps.byName[ps.fallback]; !ok {
+			return nil, fmt.Errorf("%s: default profile %q is not defined", file, ps.fallback)
+		}
+	}
+	for _, id := range sortedKeys(ps.agents) {
+		if _, ok := ps.byName[ps.agents[id]]; !ok {
+			return nil, fmt.Errorf("%s: agent %q uses undefined profile %q", file, id, ps.agents[id])
+		}
+	}
+	return ps, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// For returns the profile assigned to agent id. A nil Profiles gives every
+// agent DefaultProfile.
+func (ps *Profiles) For(id string) CognitionProfile {
+	if ps == nil {
+		return DefaultProfile()
+	}
+	if name, ok := ps.agents[id]; ok {
+		return ps.byName[name]
+	}
+	if ps.fallback != "" {
+		return ps.byName[ps.fallback]
+	}
+	return DefaultProfile()
+}
+
+// Apply gives a the profile assigned to its id, if a can be tuned. A nil
+// Profiles leaves a as it is.
+func (ps *Profiles) Apply(a Agent) {
+	if ps == nil {
+		return
+	}
+	if p, ok := a.(Profiled); ok {
+		p.SetProfile(ps.For(a.ID()))
+	}
+}
@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+const testProfiles = `{
+  "profiles": {
+    "bold":     {"caution": 10, "moveCost": 5},
+    "deaf":     {"beliefRadius": 0}
+  },
+  "agents":  {"N": "deaf"},
+  "default": "bold"
+}`
+
+func TestParseProfiles_OverridesOnlyGivenParameters(t *testing.T) {
+	ps, err := ParseProfiles(strings.NewReader(testProfiles), "test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultProfile()
+	want.Name, want.Caution, want.MoveCost = "bold", 10, 5
+	if got := ps.For("anyone"); got != want {
+		t.Fatalf("default profile = %+v, want %+v", got, want)
+	}
+	if got := ps.For("N"); got.Name != "deaf" || got.BeliefRadius != 0 || got.Paranoia != ParanoiaThreshold {
+		t.Fatalf("assigned profile = %+v", got)
+	}
+	var none *Profiles
+	if got := none.For("N"); got != DefaultProfile() {
+		t.Fatalf("nil profiles gave %+v", got)
+	}
+}
+
+func TestParseProfiles_RejectsBadFiles(t *testing.T) {
+	for name, doc := range map[string]string{
+		"unknown parameter": `{"profiles": {"x": {"bravery": 3}}}`,
+		"negative":          `{"profiles": {"x": {"caution": -1}}}`,
+		"missing default":   `{"default": "x"}`,
+		"missing assigned":  `{"profiles": {"x": {}}, "agents": {"A": "y"}}`,
+	} {
+		if _, err := ParseProfiles(strings.NewReader(doc), "test.json"); err == nil {
+			t.Errorf("%s: parsed without error", name)
+		}
+	}
+}
+
+func TestProfile_BoldAgentMovesOnStaleBelief(t *testing.T) {
+	ps, err := ParseProfiles(strings.NewReader(testProfiles), "test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewScripted("T")
+	ps.Apply(s)
+	tick := 10
+	target := core.Position{X: 1, Y: 0}
+	// Stale enough to make a default agent observe (see caution_test.go).
+	s.memory.tiles[target] = MemoryTile{Tile: core.TileView{Position: target}, LastSeen: tick - (CautionThreshold + 1)}
+
+	act := s.Decide(fakeSnapPos{pos: core.Position{X: 0, Y: 0}, tick: tick})
+	if act != MOVE_E {
+		t.Fatalf("bold agent chose %v, want MOVE_E", act)
+	}
+	if s.Energy() != MaxEnergy-5 {
+		t.Fatalf("energy after move = %d, want %d", s.Energy(), MaxEnergy-5)
+	}
+}
+
+func TestProfile_BeliefRadiusLimitsContagion(t *testing.T) {
+	beliefSignals = map[string]BeliefSignal{}
+	tick := 5
+	target := core.Position{X: 3, Y: 3}
+	emitBeliefSignal("A", tick, core.Position{X: 0, Y: 0}, []Belief{{Tile: core.TileView{Position: target, Glyph: 'X'}, Age: 0}}, nil)
+
+	deaf := DefaultProfile()
+	deaf.BeliefRadius = 0
+	mem := NewMemory()
+	if applied := applyBeliefContagion("B", core.Position{X: 1, Y: 0}, tick, mem, MaxEnergy, deaf); len(applied) != 0 {
+		t.Fatalf("deaf agent adopted %v", applied)
+	}
+	if applied := applyBeliefContagion("B", core.Position{X: 1, Y: 0}, tick, mem, MaxEnergy, DefaultProfile()); len(applied) != 1 {
+		t.Fatalf("default agent adopted %v, want one belief", applied)
+	}
+}
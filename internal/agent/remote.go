@@ -16,6 +16,15 @@ type RemoteHuman struct {
     memory *Memory
     energy int
 
+    // introspection history, captured like Human's so it survives restarts
+    snaps snapshotRing
+    saved saveMark
+
+    // stats counts what happened in the last decision.
+    stats DecisionStats
+
+    profile CognitionProfile
+
     // Channels populated by server connection goroutines.
     SendObservation chan Observation // server -> client
     RecvInput chan string           // client -> server (single-key string)
@@ -29,6 +38,7 @@ func NewRemoteHumanFromExisting(id string, mem *Memory, energy int) *RemoteHuman
         id: id,
         memory: mem,
         energy: energy,
+        profile: DefaultProfile(),
         SendObservation: make(chan Observation, 1),
         RecvInput: make(chan string, 1),
     }
@@ -87,19 +97,19 @@ func (r *RemoteHuman) DecideWithInput(snapshot Snapshot, input string) Action {
 
     // 2. Apply contagion (belief signals must have been emitted by the
     // runtime emission pass before this method is called).
-    _ = applyBeliefContagion(r.id, pos, tick, r.memory, r.energy)
+    transfers := applyBeliefContagion(r.id, pos, tick, r.memory, r.energy, r.profile)
+    rumours := applyEntityContagion(r.id, pos, tick, r.memory, r.profile)
 
     // 4. Detect & apply conflicts
-    detectAndApplyConflicts(r.memory, prev, tick)
+    scars := detectAndApplyConflicts(r.memory, prev, tick, r.profile)
+
+    // Let go of what the profile cannot hold on to.
+    forgotten := r.memory.Forget(tick, r.profile, snapshotSeed(snapshot))
 
     // 5. Build Observation
-    effectiveParanoia := ParanoiaThreshold
-    effectiveCaution := CautionThreshold
-    if r.energy < LowEnergyThreshold {
-        effectiveParanoia = ParanoiaThreshold - 2
-        effectiveCaution = CautionThreshold - 1
-    }
+    effectiveParanoia, effectiveCaution := r.profile.thresholds(r.energy)
     obs := buildObservation(r.memory, snapshot, prev, r.energy, effectiveParanoia)
+    r.stats = DecisionStats{Transfers: len(transfers) + rumours, Scars: scars, Hallucinations: obs.Hallucinated, Forgotten: forgotten}
 
     // 6. Translate provided input to intended Action
     intended := keyToAction(input)
@@ -125,32 +135,16 @@ func (r *RemoteHuman) DecideWithInput(snapshot Snapshot, input string) Action {
     }
 
     // 10. Apply energy effects
-    switch final {
-    case MOVE_N, MOVE_S, MOVE_E, MOVE_W:
-        r.energy -= MoveEnergyCost
-    case OBSERVE:
-        r.energy -= ObserveEnergyCost
-    case WAIT:
-        r.energy += WaitEnergyRestore
-    }
-    if r.energy > MaxEnergy {
-        r.energy = MaxEnergy
-    }
-    if r.energy < MinEnergy {
-        r.energy = MinEnergy
-    }
+    r.energy = r.profile.spend(r.energy, final)
 
     // 11. OBSERVE healing
-    if final == OBSERVE && r.memory != nil {
-        for pos, mt := range r.memory.tiles {
-            if mt.ScarLevel > 0 {
-                mt.ScarLevel -= 1
-                if mt.ScarLevel < 0 {
-                    mt.ScarLevel = 0
-                }
-                r.memory.tiles[pos] = mt
-            }
-        }
+    if final == OBSERVE {
+        r.memory.healScars()
+    }
+
+    // 12. Snapshot capture: record introspection after the action resolves.
+    if r.memory != nil {
+        r.snaps.append(IntrospectionSnapshot{Tick: obs.Tick, Report: Introspect(*r.memory, obs.Tick)})
     }
 
     return final
@@ -163,10 +157,7 @@ func (r *RemoteHuman) Observe(snapshot Snapshot) {
     if r.memory != nil {
         prev = r.memory.UpdateFromVisible(snapshot)
     }
-    effectiveParanoia := ParanoiaThreshold
-    if r.energy < LowEnergyThreshold {
-        effectiveParanoia = ParanoiaThreshold - 2
-    }
+    effectiveParanoia, _ := r.profile.thresholds(r.energy)
     obs := buildObservation(r.memory, snapshot, prev, r.energy, effectiveParanoia)
     select {
     case r.SendObservation <- obs:
@@ -197,5 +188,5 @@ func (r *RemoteHuman) EmitBeliefs(snapshot Snapshot) {
     if t, ok := snapshot.(interface{ TickValue() int }); ok {
         tick = t.TickValue()
     }
-    emitBeliefSignal(r.id, tick, pos, beliefs)
+    emitBeliefSignal(r.id, tick, pos, beliefs, r.memory.EntityBeliefs(tick))
 }
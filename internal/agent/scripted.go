@@ -1,6 +1,10 @@
 package agent
 
-import "github.com/divijg19/Nightshade/internal/core"
+import (
+	"sort"
+
+	"github.com/divijg19/Nightshade/internal/core"
+)
 
 // BeliefSignal is an agent-local emission used for contagion among agents
 // within a tick. It is stored in a package-level registry keyed by agent
@@ -8,6 +12,9 @@ import "github.com/divijg19/Nightshade/internal/core"
 type BeliefSignal struct {
 	Position core.Position
 	Beliefs  []Belief
+
+	// Entities are the sender's sightings of other entities.
+	Entities []EntityBelief
 }
 
 var beliefSignals = map[string]BeliefSignal{}
@@ -26,12 +33,12 @@ func manhattan(a, b core.Position) int {
 }
 
 // emitBeliefSignal stores this agent's belief signal for the current tick.
-func emitBeliefSignal(id string, tick int, pos core.Position, beliefs []Belief) {
+func emitBeliefSignal(id string, tick int, pos core.Position, beliefs []Belief, entities []EntityBelief) {
 	if beliefSignalsTick != tick {
 		beliefSignals = map[string]BeliefSignal{}
 		beliefSignalsTick = tick
 	}
-	beliefSignals[id] = BeliefSignal{Position: pos, Beliefs: beliefs}
+	beliefSignals[id] = BeliefSignal{Position: pos, Beliefs: beliefs, Entities: entities}
 }
 
 // GetBeliefSignals returns a copy of the current beliefSignals map. This is
@@ -44,16 +51,30 @@ func GetBeliefSignals() map[string]BeliefSignal {
 	return out
 }
 
+// signalSenders returns the ids that emitted a signal this tick, sorted,
+// so contagion visits senders in the same order every run.
+func signalSenders() []string {
+	ids := make([]string, 0, len(beliefSignals))
+	for id := range beliefSignals {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 // applyBeliefContagion applies signals emitted by other agents to the
-// receiver's memory according to the contagion rules. Returns a list of
-// positions that were transferred (for debug/tests).
-func applyBeliefContagion(receiverID string, receiverPos core.Position, tick int, receiverMem *Memory, receiverEnergy int) []core.Position {
+// receiver's memory according to the contagion rules, using the receiver's
+// profile: how far it listens, how strongly it holds its own beliefs and
+// how much an adopted belief is weakened. Returns a list of positions that
+// were transferred (for debug/tests).
+func applyBeliefContagion(receiverID string, receiverPos core.Position, tick int, receiverMem *Memory, receiverEnergy int, p CognitionProfile) []core.Position {
 	applied := []core.Position{}
-	for senderID, sig := range beliefSignals {
+	for _, senderID := range signalSenders() {
+		sig := beliefSignals[senderID]
 		if senderID == receiverID {
 			continue
 		}
-		if manhattan(sig.Position, receiverPos) > BeliefRadius {
+		if manhattan(sig.Position, receiverPos) > p.BeliefRadius {
 			continue
 		}
 		for _, b := range sig.Beliefs {
@@ -73,8 +94,8 @@ func applyBeliefContagion(receiverID string, receiverPos core.Position, tick int
 				// Asymmetric dominance: compare strengths using ScarLevel
 				ageA := tick - senderLastSeen
 				ageB := tick - receiverLastSeen
-				strengthA := ParanoiaThreshold - ageA
-				strengthB := ParanoiaThreshold - ageB
+				strengthA := p.Paranoia - ageA
+				strengthB := p.Paranoia - ageB
 				if strengthA < 0 {
 					strengthA = 0
 				}
@@ -94,7 +115,7 @@ func applyBeliefContagion(receiverID string, receiverPos core.Position, tick int
 			if cur, ok := receiverMem.GetMemoryTile(pos); ok {
 				scar = cur.ScarLevel
 			}
-			receiverMem.tiles[pos] = MemoryTile{Tile: b.Tile, LastSeen: tick - TransferPenalty, ScarLevel: scar}
+			receiverMem.set(pos, MemoryTile{Tile: b.Tile, LastSeen: tick - p.TransferPenalty, ScarLevel: scar})
 			applied = append(applied, pos)
 		}
 	}
@@ -103,19 +124,22 @@ func applyBeliefContagion(receiverID string, receiverPos core.Position, tick int
 
 // detectAndApplyConflicts examines memory changes (prev map returned by
 // UpdateFromVisible) and current memory to find conflicting beliefs and
-// applies scars deterministically when conflict strength thresholds are met.
-func detectAndApplyConflicts(mem *Memory, prev map[core.Position]MemoryTile, tick int) {
+// applies scars deterministically when the strength thresholds of profile p
+// are met. It returns the number of tiles scarred.
+func detectAndApplyConflicts(mem *Memory, prev map[core.Position]MemoryTile, tick int, p CognitionProfile) int {
 	if mem == nil {
-		return
+		return 0
 	}
-	for pos, newMt := range mem.tiles {
+	scarred := 0
+	for _, pos := range mem.positions() {
+		newMt := mem.tiles[pos]
 		if oldMt, ok := prev[pos]; ok {
 			if oldMt.Tile.Glyph != newMt.Tile.Glyph {
 				// compute ages
 				ageOld := tick - oldMt.LastSeen
 				ageNew := tick - newMt.LastSeen
-				strOld := ParanoiaThreshold - ageOld
-				strNew := ParanoiaThreshold - ageNew
+				strOld := p.Paranoia - ageOld
+				strNew := p.Paranoia - ageNew
 				if strOld < 0 {
 					strOld = 0
 				}
@@ -124,18 +148,20 @@ func detectAndApplyConflicts(mem *Memory, prev map[core.Position]MemoryTile, tic
 				}
 				strOld += oldMt.ScarLevel
 				strNew += newMt.ScarLevel
-				if strOld >= ConflictThreshold && strNew >= ConflictThreshold {
+				if strOld >= p.ConflictThreshold && strNew >= p.ConflictThreshold {
 					// Apply scar to the current memory entry
 					nm := mem.tiles[pos]
 					nm.ScarLevel += 1
-					if nm.LastSeen < tick-ScarPenalty {
-						nm.LastSeen = tick - ScarPenalty
+					if nm.LastSeen < tick-p.ScarPenalty {
+						nm.LastSeen = tick - p.ScarPenalty
 					}
-					mem.tiles[pos] = nm
+					mem.set(pos, nm)
+					scarred++
 				}
 			}
 		}
 	}
+	return scarred
 }
 
 // computeTarget returns the target position for a MOVE action relative to
@@ -162,7 +188,8 @@ func computeTarget(pos core.Position, a Action) (core.Position, bool) {
 // memory and are not used to update memory.
 // buildObservation constructs an Observation from the given memory and
 // runtime snapshot. It injects hallucinated tiles from memory when a
-// MemoryTile's Age > effectiveParanoia. If prevLastSeen is provided it
+// MemoryTile's Age > effectiveParanoia, and hallucinated entities from
+// sightings past the same threshold. If prevLastSeen is provided it
 // is used to preserve hallucination state across an UpdateFromVisible
 // (useful when energy is critical and OBSERVE should not clear hallucinations).
 func buildObservation(mem *Memory, snapshot interface{}, prevLastSeen map[core.Position]MemoryTile, energy int, effectiveParanoia int) Observation {
@@ -177,6 +204,7 @@ func buildObservation(mem *Memory, snapshot interface{}, prevLastSeen map[core.P
 
 	// Build Known beliefs from memory: compute Age = obs.Tick - LastSeen
 	known := []Belief{}
+	hallucinated := 0
 	visMap := make(map[core.Position]struct{})
 	for _, vv := range vis {
 		visMap[vv.Position] = struct{}{}
@@ -212,24 +240,108 @@ func buildObservation(mem *Memory, snapshot interface{}, prevLastSeen map[core.P
 				if _, ok := visMap[mt.Tile.Position]; !ok {
 					vis = append(vis, mt.Tile)
 					visMap[mt.Tile.Position] = struct{}{}
+					hallucinated++
 				}
 			}
 		}
 	}
 
-	return Observation{Visible: vis, Known: known, Tick: tick}
+	radius := 0
+	if rv, ok := snapshot.(interface{ VisibilityRadiusValue() int }); ok {
+		radius = rv.VisibilityRadiusValue()
+	}
+
+	var entities []core.EntityView
+	if ev, ok := snapshot.(interface{ EntitiesValue() []core.EntityView }); ok {
+		entities = ev.EntitiesValue()
+	}
+
+	// Entity beliefs hallucinate like tiles: a sighting older than the
+	// paranoia threshold puts the entity back in view where it is likely
+	// to be, unless it really is in view or the agent already found that
+	// spot empty.
+	var knownEntities []EntityBelief
+	if mem != nil {
+		knownEntities = mem.EntityBeliefs(tick)
+		self := core.Position{}
+		if p, ok := snapshot.(interface{ PositionValue() core.Position }); ok {
+			self = p.PositionValue()
+		}
+		inView := map[string]struct{}{}
+		for _, e := range entities {
+			inView[e.ID] = struct{}{}
+		}
+		threshold := effectiveParanoia
+		if energy < LowEnergyThreshold {
+			threshold -= 2
+		}
+		for _, eb := range knownEntities {
+			if _, ok := inView[eb.ID]; ok || eb.Stale || eb.Age <= threshold || eb.Likely == self {
+				continue
+			}
+			entities = append(entities, core.EntityView{ID: eb.ID, Position: eb.Likely})
+			hallucinated++
+		}
+	}
+
+	var sounds []core.SoundCue
+	if sv, ok := snapshot.(interface{ SoundsValue() []core.SoundCue }); ok {
+		sounds = sv.SoundsValue()
+	}
+
+	return Observation{Visible: vis, Known: known, Tick: tick, Radius: radius, Entities: entities, KnownEntities: knownEntities, Sounds: sounds, Hallucinated: hallucinated}
 }
 
 type Scripted struct {
 	id     string
 	memory *Memory
 	energy int
+
+	// stats counts what happened in the last decision.
+	stats DecisionStats
+
+	profile CognitionProfile
+
+	// goal, when set, replaces the default eastward walk: the agent steps
+	// toward the goal and holds position once it arrives.
+	goal *core.Position
 }
 
 func NewScripted(id string) *Scripted {
 	return &Scripted{id: id,
-		memory: NewMemory(),
-		energy: MaxEnergy,
+		memory:  NewMemory(),
+		energy:  MaxEnergy,
+		profile: DefaultProfile(),
+	}
+}
+
+// NewSeeker returns a Scripted agent that walks toward goal (for example a
+// control zone center) and waits there.
+func NewSeeker(id string, goal core.Position) *Scripted {
+	s := NewScripted(id)
+	s.goal = &goal
+	return s
+}
+
+// SetGoal points the agent at a new goal.
+func (s *Scripted) SetGoal(goal core.Position) {
+	s.goal = &goal
+}
+
+// stepToward returns the move that brings from one step closer to to,
+// closing the horizontal distance first. It returns WAIT when already there.
+func stepToward(from, to core.Position) Action {
+	switch {
+	case to.X > from.X:
+		return MOVE_E
+	case to.X < from.X:
+		return MOVE_W
+	case to.Y > from.Y:
+		return MOVE_S
+	case to.Y < from.Y:
+		return MOVE_N
+	default:
+		return WAIT
 	}
 }
 
@@ -266,28 +378,31 @@ func (s *Scripted) Decide(snapshot Snapshot) Action {
 	// emitBeliefSignal is kept here for compatibility, but the runtime also
 	// performs an emission pass calling EmitBeliefs on all agents before
 	// applying contagion to ensure simultaneous signals.
-	emitBeliefSignal(s.id, tick, pos, beliefs)
+	emitBeliefSignal(s.id, tick, pos, beliefs, s.memory.EntityBeliefs(tick))
 
 	// Apply contagion from earlier emitters in this tick (asymmetric)
-	_ = applyBeliefContagion(s.id, pos, tick, s.memory, s.energy)
+	transfers := applyBeliefContagion(s.id, pos, tick, s.memory, s.energy, s.profile)
+	rumours := applyEntityContagion(s.id, pos, tick, s.memory, s.profile)
 
 	// After contagion, detect conflicts and apply scars deterministically.
-	detectAndApplyConflicts(s.memory, prev, tick)
+	scars := detectAndApplyConflicts(s.memory, prev, tick, s.profile)
+
+	// Let go of what the profile cannot hold on to.
+	forgotten := s.memory.Forget(tick, s.profile, snapshotSeed(snapshot))
 
 	// Compute effective thresholds based on energy
-	effectiveParanoia := ParanoiaThreshold
-	effectiveCaution := CautionThreshold
-	if s.energy < LowEnergyThreshold {
-		effectiveParanoia = ParanoiaThreshold - 2
-		effectiveCaution = CautionThreshold - 1
-	}
+	effectiveParanoia, effectiveCaution := s.profile.thresholds(s.energy)
 
 	// Build agent-side Observation using helper (includes hallucinations).
 	obs := buildObservation(s.memory, snapshot, prev, s.energy, effectiveParanoia)
+	s.stats = DecisionStats{Transfers: len(transfers) + rumours, Scars: scars, Hallucinations: obs.Hallucinated, Forgotten: forgotten}
 
 	// Decision flow: compute intended action (existing behavior), then
 	// potentially override with OBSERVE if target belief is stale.
 	intended := MOVE_E
+	if s.goal != nil {
+		intended = stepToward(pos, *s.goal)
+	}
 
 	// If intended is a move, compute target from agent's current position and
 	// mark that we should OBSERVE instead of moving if the target belief is stale.
@@ -310,33 +425,12 @@ func (s *Scripted) Decide(snapshot Snapshot) Action {
 	}
 
 	// Apply energy effects after final action selection
-	switch final {
-	case MOVE_N, MOVE_S, MOVE_E, MOVE_W:
-		s.energy -= MoveEnergyCost
-	case OBSERVE:
-		s.energy -= ObserveEnergyCost
-	case WAIT:
-		s.energy += WaitEnergyRestore
-	}
-	if s.energy > MaxEnergy {
-		s.energy = MaxEnergy
-	}
-	if s.energy < MinEnergy {
-		s.energy = MinEnergy
-	}
+	s.energy = s.profile.spend(s.energy, final)
 
 	// OBSERVE healing: reduce ScarLevel by 1 for scarred memories when agent
 	// successfully performs OBSERVE (partial healing per tick).
-	if final == OBSERVE && s.memory != nil {
-		for pos, mt := range s.memory.tiles {
-			if mt.ScarLevel > 0 {
-				mt.ScarLevel -= 1
-				if mt.ScarLevel < 0 {
-					mt.ScarLevel = 0
-				}
-				s.memory.tiles[pos] = mt
-			}
-		}
+	if final == OBSERVE {
+		s.memory.healScars()
 	}
 
 	_ = obs
@@ -368,7 +462,7 @@ func (s *Scripted) EmitBeliefs(snapshot Snapshot) {
 	if t, ok := snapshot.(interface{ TickValue() int }); ok {
 		tick = t.TickValue()
 	}
-	emitBeliefSignal(s.id, tick, pos, beliefs)
+	emitBeliefSignal(s.id, tick, pos, beliefs, s.memory.EntityBeliefs(tick))
 }
 
 // Energy returns the current energy level for debug/inspection.
@@ -379,12 +473,18 @@ type Oscillating struct {
 	id     string
 	memory *Memory
 	energy int
+
+	// stats counts what happened in the last decision.
+	stats DecisionStats
+
+	profile CognitionProfile
 }
 
 func NewOscillating(id string) *Oscillating {
 	return &Oscillating{id: id,
-		memory: NewMemory(),
-		energy: MaxEnergy,
+		memory:  NewMemory(),
+		energy:  MaxEnergy,
+		profile: DefaultProfile(),
 	}
 }
 
@@ -417,22 +517,21 @@ func (o *Oscillating) Decide(snapshot Snapshot) Action {
 	if t, ok := snapshot.(interface{ TickValue() int }); ok {
 		tick = t.TickValue()
 	}
-	emitBeliefSignal(o.id, tick, opos, obeliefs)
-	_ = applyBeliefContagion(o.id, opos, tick, o.memory, o.energy)
+	emitBeliefSignal(o.id, tick, opos, obeliefs, o.memory.EntityBeliefs(tick))
+	transfers := applyBeliefContagion(o.id, opos, tick, o.memory, o.energy, o.profile)
+	rumours := applyEntityContagion(o.id, opos, tick, o.memory, o.profile)
 
 	// After contagion, detect conflicts and apply scars deterministically.
-	detectAndApplyConflicts(o.memory, prev, tick)
+	scars := detectAndApplyConflicts(o.memory, prev, tick, o.profile)
 
-	effectiveParanoia := ParanoiaThreshold
-	effectiveCaution := CautionThreshold
-	if o.energy < LowEnergyThreshold {
-		effectiveParanoia = ParanoiaThreshold - 2
-		effectiveCaution = CautionThreshold - 1
-	}
+	// Let go of what the profile cannot hold on to.
+	forgotten := o.memory.Forget(tick, o.profile, snapshotSeed(snapshot))
+
+	effectiveParanoia, effectiveCaution := o.profile.thresholds(o.energy)
 
 	// Build agent-side Observation using helper (includes hallucinations).
 	obs := buildObservation(o.memory, snapshot, prev, o.energy, effectiveParanoia)
-	_ = obs
+	o.stats = DecisionStats{Transfers: len(transfers) + rumours, Scars: scars, Hallucinations: obs.Hallucinated, Forgotten: forgotten}
 
 	// Decide using tick parity as before, then apply caution check.
 	intended := MOVE_N
@@ -462,20 +561,7 @@ func (o *Oscillating) Decide(snapshot Snapshot) Action {
 		final = WAIT
 	}
 
-	switch final {
-	case MOVE_N, MOVE_S, MOVE_E, MOVE_W:
-		o.energy -= MoveEnergyCost
-	case OBSERVE:
-		o.energy -= ObserveEnergyCost
-	case WAIT:
-		o.energy += WaitEnergyRestore
-	}
-	if o.energy > MaxEnergy {
-		o.energy = MaxEnergy
-	}
-	if o.energy < MinEnergy {
-		o.energy = MinEnergy
-	}
+	o.energy = o.profile.spend(o.energy, final)
 
 	return final
 }
@@ -508,5 +594,5 @@ func (o *Oscillating) EmitBeliefs(snapshot Snapshot) {
 	if t, ok := snapshot.(interface{ TickValue() int }); ok {
 		tick = t.TickValue()
 	}
-	emitBeliefSignal(o.id, tick, opos, obeliefs)
+	emitBeliefSignal(o.id, tick, opos, obeliefs, o.memory.EntityBeliefs(tick))
 }
@@ -1,6 +1,10 @@
 package agent
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/core"
+)
 
 type testSnap struct{ t int }
 
@@ -21,3 +25,24 @@ func TestOscillatingAgent_Parity(t *testing.T) {
 		t.Fatalf("odd tick: got %v, want MOVE_S", act)
 	}
 }
+
+func TestSeeker_StepsTowardGoalThenWaits(t *testing.T) {
+	goal := core.Position{X: 1, Y: 2}
+	s := NewSeeker("G", goal)
+
+	cases := []struct {
+		pos  core.Position
+		want Action
+	}{
+		{core.Position{X: 0, Y: 0}, MOVE_E},
+		{core.Position{X: 3, Y: 0}, MOVE_W},
+		{core.Position{X: 1, Y: 0}, MOVE_S},
+		{core.Position{X: 1, Y: 4}, MOVE_N},
+		{goal, WAIT},
+	}
+	for i, c := range cases {
+		if got := s.Decide(fakeSnapPos{pos: c.pos, tick: i}); got != c.want {
+			t.Fatalf("at %+v: got %v, want %v", c.pos, got, c.want)
+		}
+	}
+}
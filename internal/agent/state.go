@@ -0,0 +1,96 @@
+package agent
+
+// SaveState is everything a human-controlled agent carries between server
+// restarts: energy, memory (including scars and entity sightings) and the
+// introspection history
+// shown by replay. Position belongs to the runtime and is saved alongside
+// by the caller.
+type SaveState struct {
+	Energy   int
+	Memory   []MemoryTile
+	Entities []EntityMemory
+
+	// Introspection holds captured snapshots, oldest first.
+	Introspection []IntrospectionSnapshot
+}
+
+// saveMark records what an agent looked like when it was last saved.
+type saveMark struct {
+	valid  bool
+	energy int
+	snaps  int
+}
+
+func (m saveMark) dirty(energy int, ring *snapshotRing, mem *Memory) bool {
+	return !m.valid || m.energy != energy || m.snaps != ring.total || mem.Dirty()
+}
+
+// history returns the ring contents oldest first.
+func (r *snapshotRing) history() []IntrospectionSnapshot {
+	out := make([]IntrospectionSnapshot, 0, r.count)
+	for i := r.count - 1; i >= 0; i-- {
+		s, _ := r.getFromNewest(i)
+		out = append(out, s)
+	}
+	return out
+}
+
+// restoreState rebuilds memory, energy and the snapshot ring from st.
+func restoreState(st SaveState) (*Memory, int, snapshotRing) {
+	mem := NewMemoryFrom(st.Memory, st.Entities)
+	var ring snapshotRing
+	for _, s := range st.Introspection {
+		ring.append(s)
+	}
+	return mem, st.Energy, ring
+}
+
+// State returns a copy of the agent's persistent state.
+func (h *Human) State() SaveState {
+	return SaveState{Energy: h.energy, Memory: h.memory.All(), Entities: h.memory.Entities(), Introspection: h.snaps.history()}
+}
+
+// Restore replaces the agent's persistent state with st. The restored
+// state counts as saved.
+func (h *Human) Restore(st SaveState) {
+	h.memory, h.energy, h.snaps = restoreState(st)
+	h.MarkClean()
+}
+
+// Dirty reports whether the agent's persistent state changed since the last
+// MarkClean. A new agent is dirty until it is first saved.
+func (h *Human) Dirty() bool {
+	return h.saved.dirty(h.energy, &h.snaps, h.memory)
+}
+
+// MarkClean records that the current state has been saved.
+func (h *Human) MarkClean() {
+	h.saved = saveMark{valid: true, energy: h.energy, snaps: h.snaps.total}
+	h.memory.MarkClean()
+}
+
+// State returns a copy of the agent's persistent state.
+func (r *RemoteHuman) State() SaveState {
+	return SaveState{Energy: r.energy, Memory: r.memory.All(), Entities: r.memory.Entities(), Introspection: r.snaps.history()}
+}
+
+// NewRemoteHumanFromState constructs a RemoteHuman exactly as it was saved.
+func NewRemoteHumanFromState(id string, st SaveState) *RemoteHuman {
+	mem, energy, ring := restoreState(st)
+	r := NewRemoteHumanFromExisting(id, mem, energy)
+	r.snaps = ring
+	r.MarkClean()
+	return r
+}
+
+// Dirty reports whether the agent's persistent state changed since the last
+// MarkClean. A new agent is dirty until it is first saved.
+func (r *RemoteHuman) Dirty() bool {
+	return r.saved.dirty(r.energy, &r.snaps, r.memory)
+}
+
+// MarkClean records that the current state has been saved.
+func (r *RemoteHuman) MarkClean() {
+	r.saved = saveMark{valid: true, energy: r.energy, snaps: r.snaps.total}
+	r.memory.MarkClean()
+}
@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+func TestRemoteHumanState_RoundTrip(t *testing.T) {
+	r := NewRemoteHumanFromExisting("R", NewMemory(), MaxEnergy)
+	pos := core.Position{X: 2, Y: 3}
+	r.memory.SetMemoryTile(pos, MemoryTile{Tile: core.TileView{Position: pos, Glyph: '#'}, LastSeen: 1, ScarLevel: 2})
+	r.DecideWithInput(fakeSnap{tick: 4}, "d")
+	r.DecideWithInput(fakeSnap{tick: 5}, "")
+
+	st := r.State()
+	if len(st.Introspection) != 2 || st.Introspection[0].Tick != 4 || st.Introspection[1].Tick != 5 {
+		t.Fatalf("introspection history = %+v", st.Introspection)
+	}
+
+	back := NewRemoteHumanFromState("R", st)
+	if !reflect.DeepEqual(back.State(), st) {
+		t.Fatalf("restored state differs:\n got %+v\nwant %+v", back.State(), st)
+	}
+	if mt, ok := back.Memory().GetMemoryTile(pos); !ok || mt.ScarLevel != 2 {
+		t.Fatalf("scarred tile not restored: %+v", mt)
+	}
+}
+
+func TestSnapshotRing_HistoryKeepsNewest(t *testing.T) {
+	var ring snapshotRing
+	for i := 0; i < 40; i++ {
+		ring.append(IntrospectionSnapshot{Tick: i})
+	}
+	h := ring.history()
+	if len(h) != 32 || h[0].Tick != 8 || h[31].Tick != 39 {
+		t.Fatalf("history = %d entries from %d to %d", len(h), h[0].Tick, h[len(h)-1].Tick)
+	}
+}
+
+func TestRemoteHuman_DirtyTracking(t *testing.T) {
+	r := NewRemoteHumanFromExisting("R", NewMemory(), MaxEnergy)
+	if !r.Dirty() {
+		t.Fatal("new agent should be dirty until first saved")
+	}
+	r.MarkClean()
+	if r.Dirty() {
+		t.Fatal("agent dirty right after MarkClean")
+	}
+
+	r.Memory().SetMemoryTile(core.Position{X: 1}, MemoryTile{LastSeen: 1})
+	if !r.Dirty() {
+		t.Fatal("memory change not tracked")
+	}
+	r.MarkClean()
+
+	r.DecideWithInput(fakeSnap{tick: 2}, "d")
+	if !r.Dirty() {
+		t.Fatal("decision (energy and introspection) not tracked")
+	}
+
+	restored := NewRemoteHumanFromState("R", r.State())
+	if restored.Dirty() {
+		t.Fatal("freshly restored agent should be clean")
+	}
+}
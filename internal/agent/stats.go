@@ -0,0 +1,39 @@
+package agent
+
+// DecisionStats counts what the cognition pipeline did during one decision.
+// The runtime reads it after each tick for metrics and the spectator camera.
+type DecisionStats struct {
+	// Transfers is the number of beliefs adopted from nearby agents.
+	Transfers int
+	// Scars is the number of memory tiles scarred by conflicting observations.
+	Scars int
+	// Hallucinations is the number of remembered tiles injected into Visible.
+	Hallucinations int
+	// Forgotten is the number of beliefs lost to decay or capacity.
+	Forgotten int
+}
+
+// LastDecision returns the counts from the agent's most recent decision.
+func (h *Human) LastDecision() DecisionStats { return h.stats }
+
+// Hallucinations returns how many remembered tiles the agent mistook for
+// visible ones in its last decision.
+func (h *Human) Hallucinations() int { return h.stats.Hallucinations }
+
+// LastDecision returns the counts from the agent's most recent decision.
+func (r *RemoteHuman) LastDecision() DecisionStats { return r.stats }
+
+// Hallucinations returns Hallucinations from the last decision.
+func (r *RemoteHuman) Hallucinations() int { return r.stats.Hallucinations }
+
+// LastDecision returns the counts from the agent's most recent decision.
+func (s *Scripted) LastDecision() DecisionStats { return s.stats }
+
+// Hallucinations returns Hallucinations from the last decision.
+func (s *Scripted) Hallucinations() int { return s.stats.Hallucinations }
+
+// LastDecision returns the counts from the agent's most recent decision.
+func (o *Oscillating) LastDecision() DecisionStats { return o.stats }
+
+// Hallucinations returns Hallucinations from the last decision.
+func (o *Oscillating) Hallucinations() int { return o.stats.Hallucinations }
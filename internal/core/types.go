@@ -9,3 +9,28 @@ type TileView struct {
 	Glyph    rune
 	Visible  bool
 }
+
+// EntityView is another entity as perceived by an observer.
+type EntityView struct {
+	ID       string
+	Position Position
+}
+
+// SoundKind names what an observer hears.
+type SoundKind string
+
+const (
+	SoundFootsteps SoundKind = "footsteps"
+	SoundStruggle  SoundKind = "struggle"
+	SoundRustling  SoundKind = "rustling"
+)
+
+// SoundCue is a noise heard from a source the observer cannot see. Direction
+// is a compass bearing from the observer ("north", "southwest", ...) and
+// Loudness falls off with distance; a cue is only reported while it is
+// above zero.
+type SoundCue struct {
+	Kind      SoundKind
+	Direction string
+	Loudness  int
+}
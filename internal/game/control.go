@@ -0,0 +1,27 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+// ZoneHolder returns the ID of the entity holding z. A zone is held by an
+// entity that occupies it alone; an empty or contested zone is held by
+// nobody and ZoneHolder returns "".
+func ZoneHolder(w *world.World, z world.Zone) string {
+	ids := w.EntityIDs()
+	sort.Strings(ids)
+	holder := ""
+	for _, id := range ids {
+		pos, _ := w.PositionOf(id)
+		if !z.Contains(pos) {
+			continue
+		}
+		if holder != "" {
+			return ""
+		}
+		holder = id
+	}
+	return holder
+}
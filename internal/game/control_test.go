@@ -0,0 +1,26 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+func TestZoneHolder_SoleOccupantHolds(t *testing.T) {
+	w := world.New(world.Width, world.Height)
+	z := world.Zone{Name: "core", Min: world.Position{X: 2, Y: 2}, Max: world.Position{X: 4, Y: 4}}
+	w.AddZone(z)
+
+	if got := ZoneHolder(w, z); got != "" {
+		t.Fatalf("empty zone held by %q", got)
+	}
+	w.SetPosition("A", world.Position{X: 3, Y: 3})
+	w.SetPosition("B", world.Position{X: 9, Y: 9})
+	if got := ZoneHolder(w, z); got != "A" {
+		t.Fatalf("holder = %q, want A", got)
+	}
+	w.SetPosition("B", world.Position{X: 4, Y: 2})
+	if got := ZoneHolder(w, z); got != "" {
+		t.Fatalf("contested zone held by %q", got)
+	}
+}
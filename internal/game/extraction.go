@@ -0,0 +1,67 @@
+package game
+
+import (
+	"github.com/divijg19/Nightshade/internal/util"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+// ExitTiming bounds how long exits stay closed and open. Durations are drawn
+// uniformly from [Min, Max] using the run's seeded RNG.
+type ExitTiming struct {
+	MinClosed, MaxClosed int
+	MinOpen, MaxOpen     int
+}
+
+// DefaultExitTiming keeps exits closed for most of a run and open only
+// briefly, so reaching one requires being near it at the right moment.
+var DefaultExitTiming = ExitTiming{
+	MinClosed: 10,
+	MaxClosed: 30,
+	MinOpen:   5,
+	MaxOpen:   15,
+}
+
+// UpdateExits opens or revokes every exit whose toggle tick has arrived and
+// schedules its next toggle. Exits are visited in placement order so the RNG
+// sequence, and therefore the schedule, depends only on the seed. An exit
+// with NextToggle == 0 that is closed is treated as unscheduled and receives
+// its first opening time.
+func UpdateExits(w *world.World, tick int, rng *util.Rand, timing ExitTiming) {
+	for i, e := range w.Exits() {
+		if !e.Open && e.NextToggle == 0 {
+			e.NextToggle = tick + rng.Range(timing.MinClosed, timing.MaxClosed)
+			w.SetExit(i, e)
+			continue
+		}
+		if tick < e.NextToggle {
+			continue
+		}
+		e.Open = !e.Open
+		if e.Open {
+			e.NextToggle = tick + rng.Range(timing.MinOpen, timing.MaxOpen)
+		} else {
+			e.NextToggle = tick + rng.Range(timing.MinClosed, timing.MaxClosed)
+		}
+		w.SetExit(i, e)
+	}
+}
+
+// ForceExits opens (or closes) every exit now, whatever its schedule, and
+// draws its next toggle from timing as if it had toggled on its own.
+func ForceExits(w *world.World, open bool, tick int, rng *util.Rand, timing ExitTiming) {
+	for i, e := range w.Exits() {
+		e.Open = open
+		if open {
+			e.NextToggle = tick + rng.Range(timing.MinOpen, timing.MaxOpen)
+		} else {
+			e.NextToggle = tick + rng.Range(timing.MinClosed, timing.MaxClosed)
+		}
+		w.SetExit(i, e)
+	}
+}
+
+// Extracted reports whether an entity at pos has reached an open exit.
+func Extracted(w *world.World, pos world.Position) bool {
+	e, ok := w.ExitAt(pos)
+	return ok && e.Open
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/util"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+func runExitSchedule(seed uint64, ticks int) [][]world.Exit {
+	w := world.New(world.Width, world.Height)
+	w.AddExit(world.Position{X: 3, Y: 3})
+	w.AddExit(world.Position{X: 10, Y: 4})
+	rng := util.NewRand(seed)
+	out := [][]world.Exit{}
+	for tick := 0; tick < ticks; tick++ {
+		UpdateExits(w, tick, rng, DefaultExitTiming)
+		out = append(out, w.Exits())
+	}
+	return out
+}
+
+func TestUpdateExits_DeterministicFromSeed(t *testing.T) {
+	a := runExitSchedule(42, 120)
+	b := runExitSchedule(42, 120)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("exit schedule differs between runs with the same seed")
+	}
+}
+
+func TestUpdateExits_OpensAndRevokes(t *testing.T) {
+	history := runExitSchedule(7, 200)
+	if history[0][0].Open {
+		t.Fatal("exit should start closed")
+	}
+	opened, revoked := false, false
+	for i := 1; i < len(history); i++ {
+		prev, cur := history[i-1][0], history[i][0]
+		if !prev.Open && cur.Open {
+			opened = true
+			if d := cur.NextToggle - i; d < DefaultExitTiming.MinOpen || d > DefaultExitTiming.MaxOpen {
+				t.Fatalf("open duration %d outside timing bounds", d)
+			}
+		}
+		if prev.Open && !cur.Open {
+			revoked = true
+		}
+	}
+	if !opened || !revoked {
+		t.Fatalf("expected exit to open and be revoked: opened=%v revoked=%v", opened, revoked)
+	}
+}
+
+func TestExtracted_OnlyOnOpenExit(t *testing.T) {
+	w := world.New(world.Width, world.Height)
+	pos := world.Position{X: 1, Y: 1}
+	w.AddExit(pos)
+	if Extracted(w, pos) {
+		t.Fatal("closed exit should not extract")
+	}
+	w.SetExit(0, world.Exit{Position: pos, Open: true})
+	if !Extracted(w, pos) {
+		t.Fatal("open exit should extract")
+	}
+	if Extracted(w, world.Position{X: 2, Y: 1}) {
+		t.Fatal("non-exit position should not extract")
+	}
+}
+
+func TestForceExits_OverridesSchedule(t *testing.T) {
+	w := world.New(world.Width, world.Height)
+	w.AddExit(world.Position{X: 3, Y: 3})
+	w.AddExit(world.Position{X: 10, Y: 4})
+	rng := util.NewRand(1)
+	ForceExits(w, true, 50, rng, DefaultExitTiming)
+	for _, e := range w.Exits() {
+		if !e.Open || e.NextToggle < 50+DefaultExitTiming.MinOpen || e.NextToggle > 50+DefaultExitTiming.MaxOpen {
+			t.Fatalf("forced open exit = %+v", e)
+		}
+	}
+	UpdateExits(w, 51, rng, DefaultExitTiming)
+	if e := w.Exits()[0]; !e.Open {
+		t.Fatal("forced exit closed before its scheduled toggle")
+	}
+	ForceExits(w, false, 52, rng, DefaultExitTiming)
+	for _, e := range w.Exits() {
+		if e.Open || e.NextToggle < 52+DefaultExitTiming.MinClosed {
+			t.Fatalf("forced closed exit = %+v", e)
+		}
+	}
+}
@@ -30,3 +30,13 @@ func ResolveMovement(pos world.Position, action agent.Action, worldWidth int,
 	}
 	return tgt
 }
+
+// ResolveMove applies ResolveMovement within w and additionally refuses to
+// move into impassable terrain such as walls.
+func ResolveMove(w *world.World, pos world.Position, action agent.Action) world.Position {
+	tgt := ResolveMovement(pos, action, w.Width(), w.Height())
+	if !w.Passable(tgt) {
+		return pos
+	}
+	return tgt
+}
@@ -31,3 +31,15 @@ func TestResolveMovement_Basic(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveMove_BlockedByWall(t *testing.T) {
+	w := world.New(world.Width, world.Height)
+	w.SetTerrain(world.Position{X: 2, Y: 1}, world.Wall)
+
+	if got := ResolveMove(w, world.Position{X: 1, Y: 1}, agent.MOVE_E); got != (world.Position{X: 1, Y: 1}) {
+		t.Fatalf("moved into wall: %+v", got)
+	}
+	if got := ResolveMove(w, world.Position{X: 1, Y: 1}, agent.MOVE_S); got != (world.Position{X: 1, Y: 2}) {
+		t.Fatalf("open move blocked: %+v", got)
+	}
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/util"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+// Noise levels by action. A noise is heard by anyone closer (in Chebyshev
+// distance) than its level. Hiding, waiting and observing are silent.
+const (
+	MoveNoise   = 6
+	GatherNoise = 4
+	AttackNoise = 10
+)
+
+// Noise returns the kind and level of the noise an action makes. Silent
+// actions return level 0.
+func Noise(a agent.Action) (core.SoundKind, int) {
+	switch a {
+	case agent.MOVE_N, agent.MOVE_S, agent.MOVE_E, agent.MOVE_W:
+		return core.SoundFootsteps, MoveNoise
+	case agent.GATHER:
+		return core.SoundRustling, GatherNoise
+	case agent.ATTACK:
+		return core.SoundStruggle, AttackNoise
+	}
+	return "", 0
+}
+
+// Hear reports the cue a listener at from perceives for an action taken by a
+// source at src, if the noise carries that far.
+func Hear(from, src world.Position, a agent.Action) (core.SoundCue, bool) {
+	kind, level := Noise(a)
+	dist := max(util.Abs(src.X-from.X), util.Abs(src.Y-from.Y))
+	if level == 0 || dist == 0 || dist >= level {
+		return core.SoundCue{}, false
+	}
+	return core.SoundCue{Kind: kind, Direction: Bearing(from, src), Loudness: level - dist}, true
+}
+
+// Bearing returns the eight-way compass direction from a to b. North is
+// decreasing Y. Offsets at least twice as long on one axis snap to that
+// axis.
+func Bearing(a, b world.Position) string {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	ns, ew := "", ""
+	if dy < 0 && util.Abs(dy)*2 > util.Abs(dx) {
+		ns = "north"
+	} else if dy > 0 && util.Abs(dy)*2 > util.Abs(dx) {
+		ns = "south"
+	}
+	if dx > 0 && util.Abs(dx)*2 > util.Abs(dy) {
+		ew = "east"
+	} else if dx < 0 && util.Abs(dx)*2 > util.Abs(dy) {
+		ew = "west"
+	}
+	return ns + ew
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"github.com/divijg19/Nightshade/internal/util"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+// UpdateObjects advances every world object whose period divides tick.
+// Objects are visited in placement order and use no randomness, so their
+// motion is fully determined by the stage and the tick number.
+func UpdateObjects(w *world.World, tick int) {
+	for i, o := range w.Objects() {
+		period := o.Period
+		if period < 1 {
+			period = 1
+		}
+		if tick%period != 0 {
+			continue
+		}
+		switch o.Behavior {
+		case world.Patrol:
+			o = stepPatrol(w, o)
+		case world.Blink:
+			if !o.Hidden || canEnter(w, o, o.Position) {
+				o.Hidden = !o.Hidden
+			}
+		case world.Drift:
+			if next := wrap(w, world.Position{X: o.Position.X + o.Dir.X, Y: o.Position.Y + o.Dir.Y}); canEnter(w, o, next) {
+				o.Position = next
+			}
+		}
+		w.SetObject(i, o)
+	}
+}
+
+// stepPatrol moves o one cell toward its current waypoint, horizontal leg
+// first, and turns to the next waypoint on arrival.
+func stepPatrol(w *world.World, o world.Object) world.Object {
+	if len(o.Path) == 0 {
+		return o
+	}
+	if o.Waypoint >= len(o.Path) {
+		o.Waypoint = 0
+	}
+	if o.Position == o.Path[o.Waypoint] {
+		o.Waypoint = (o.Waypoint + 1) % len(o.Path)
+	}
+	tgt := o.Path[o.Waypoint]
+	next := o.Position
+	if dx := tgt.X - o.Position.X; dx != 0 {
+		next.X += dx / util.Abs(dx)
+	} else if dy := tgt.Y - o.Position.Y; dy != 0 {
+		next.Y += dy / util.Abs(dy)
+	}
+	if canEnter(w, o, next) {
+		o.Position = next
+	}
+	return o
+}
+
+// canEnter reports whether o may be shown at pos. Solid objects wait rather
+// than land on a wall, another solid object or an entity; others go
+// anywhere.
+func canEnter(w *world.World, o world.Object, pos world.Position) bool {
+	return !o.Solid || (w.Passable(pos) && !w.Occupied(pos))
+}
+
+// wrap folds pos back onto the world, treating both axes as toroidal.
+func wrap(w *world.World, pos world.Position) world.Position {
+	pos.X = ((pos.X % w.Width()) + w.Width()) % w.Width()
+	pos.Y = ((pos.Y % w.Height()) + w.Height()) % w.Height()
+	return pos
+}
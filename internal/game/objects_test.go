@@ -0,0 +1,90 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+func TestUpdateObjects_PatrolWalksBackAndForth(t *testing.T) {
+	w := world.New(10, 10)
+	a, b := world.Position{X: 1, Y: 1}, world.Position{X: 3, Y: 1}
+	w.AddObject(world.Object{Glyph: world.GlyphSentry, Position: a, Behavior: world.Patrol, Path: []world.Position{a, b}})
+
+	want := []int{2, 3, 2, 1, 2}
+	for tick, x := range want {
+		UpdateObjects(w, tick)
+		if got := w.Objects()[0].Position; got != (world.Position{X: x, Y: 1}) {
+			t.Fatalf("tick %d: sentry at %+v, want x=%d", tick, got, x)
+		}
+	}
+}
+
+func TestUpdateObjects_BlinkAndDriftRespectPeriod(t *testing.T) {
+	w := world.New(4, 4)
+	beacon := world.Position{X: 0, Y: 0}
+	w.AddObject(world.Object{Glyph: world.GlyphBeacon, Position: beacon, Behavior: world.Blink, Period: 2})
+	w.AddObject(world.Object{Glyph: world.GlyphLight, Position: world.Position{X: 3, Y: 2}, Behavior: world.Drift, Period: 2, Dir: world.Position{X: 1}})
+
+	UpdateObjects(w, 1)
+	if w.GlyphAt(beacon) != world.GlyphBeacon {
+		t.Fatal("beacon toggled off-period")
+	}
+	UpdateObjects(w, 2)
+	if w.GlyphAt(beacon) != world.GlyphFloor {
+		t.Fatal("beacon still shown after blinking")
+	}
+	if got := w.Objects()[1].Position; got != (world.Position{X: 0, Y: 2}) {
+		t.Fatalf("light did not wrap: %+v", got)
+	}
+}
+
+func TestUpdateObjects_SolidPatrolBlocksAndWaits(t *testing.T) {
+	w := world.New(10, 10)
+	a, b := world.Position{X: 1, Y: 1}, world.Position{X: 4, Y: 1}
+	w.AddObject(world.Object{Glyph: world.GlyphWall, Position: a, Behavior: world.Patrol, Path: []world.Position{a, b}, Solid: true})
+	if w.Passable(a) {
+		t.Fatal("solid object should block its cell")
+	}
+
+	w.SetPosition("E", world.Position{X: 2, Y: 1})
+	UpdateObjects(w, 0)
+	if got := w.Objects()[0].Position; got != a {
+		t.Fatalf("moving wall pushed into entity: %+v", got)
+	}
+	w.Remove("E")
+	UpdateObjects(w, 1)
+	if got := w.Objects()[0].Position; got != (world.Position{X: 2, Y: 1}) {
+		t.Fatalf("moving wall did not resume: %+v", got)
+	}
+	if !w.Passable(a) {
+		t.Fatal("vacated cell still blocked")
+	}
+}
+
+func TestUpdateObjects_SolidBlinkAndDriftWaitForEntities(t *testing.T) {
+	w := world.New(10, 10)
+	door, light := world.Position{X: 2, Y: 1}, world.Position{X: 1, Y: 3}
+	w.AddObject(world.Object{Glyph: world.GlyphWall, Position: door, Behavior: world.Blink, Hidden: true, Solid: true})
+	w.AddObject(world.Object{Glyph: world.GlyphWall, Position: light, Behavior: world.Drift, Dir: world.Position{X: 1}, Solid: true})
+
+	w.SetPosition("A", door)
+	w.SetPosition("B", world.Position{X: 2, Y: 3})
+	UpdateObjects(w, 0)
+	if o := w.Objects()[0]; !o.Hidden {
+		t.Fatal("solid blink appeared on an entity")
+	}
+	if got := w.Objects()[1].Position; got != light {
+		t.Fatalf("solid drift moved onto an entity: %+v", got)
+	}
+
+	w.Remove("A")
+	w.Remove("B")
+	UpdateObjects(w, 1)
+	if w.Passable(door) {
+		t.Fatal("solid blink did not appear once its cell was free")
+	}
+	if got := w.Objects()[1].Position; got != (world.Position{X: 2, Y: 3}) {
+		t.Fatalf("solid drift did not resume: %+v", got)
+	}
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+// Visibility radius modifiers. Each applies at most once.
+const (
+	ObserveRadiusBonus    = 1
+	HideRadiusPenalty     = 1
+	CriticalRadiusPenalty = 1
+	DarknessRadiusPenalty = 1
+)
+
+// Perceiver is the state that determines how far an entity can see.
+type Perceiver struct {
+	LastAction agent.Action
+	Energy     int
+	Terrain    world.Terrain
+}
+
+// VisibilityRadius returns the effective radius for p given the run's base
+// radius. Observing widens the view; hiding, critical energy and standing in
+// darkness each narrow it. The result is never negative; at zero an entity
+// sees only its own cell.
+func VisibilityRadius(base int, p Perceiver) int {
+	r := base
+	switch p.LastAction {
+	case agent.OBSERVE:
+		r += ObserveRadiusBonus
+	case agent.HIDE:
+		r -= HideRadiusPenalty
+	}
+	if p.Energy < agent.CriticalEnergyThreshold {
+		r -= CriticalRadiusPenalty
+	}
+	if p.Terrain == world.Dark {
+		r -= DarknessRadiusPenalty
+	}
+	if r < 0 {
+		r = 0
+	}
+	return r
+}
+
+// Concealed reports whether an entity is hidden from ordinary sight: it hid
+// on its previous tick or stands in darkness.
+func Concealed(lastAction agent.Action, terrain world.Terrain) bool {
+	return lastAction == agent.HIDE || terrain == world.Dark
+}
+
+// Reveals reports whether an observer whose previous action was prev can see
+// a concealed entity within its view. Observing sharpens perception enough
+// to pick out anything hiding in range.
+func Reveals(prev agent.Action) bool {
+	return prev == agent.OBSERVE
+}
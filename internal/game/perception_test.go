@@ -0,0 +1,75 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+func TestVisibilityRadius_Modifiers(t *testing.T) {
+	rested := agent.MaxEnergy
+	critical := agent.CriticalEnergyThreshold - 1
+	cases := []struct {
+		name string
+		p    Perceiver
+		want int
+	}{
+		{"baseline", Perceiver{LastAction: agent.WAIT, Energy: rested}, 2},
+		{"observing", Perceiver{LastAction: agent.OBSERVE, Energy: rested}, 3},
+		{"hiding", Perceiver{LastAction: agent.HIDE, Energy: rested}, 1},
+		{"critical energy", Perceiver{LastAction: agent.WAIT, Energy: critical}, 1},
+		{"darkness", Perceiver{LastAction: agent.WAIT, Energy: rested, Terrain: world.Dark}, 1},
+		{"observing in darkness", Perceiver{LastAction: agent.OBSERVE, Energy: rested, Terrain: world.Dark}, 2},
+		{"clamped at zero", Perceiver{LastAction: agent.HIDE, Energy: critical, Terrain: world.Dark}, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := VisibilityRadius(2, c.p); got != c.want {
+				t.Fatalf("VisibilityRadius = %d, want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestConcealed_HidingOrDarkness(t *testing.T) {
+	if Concealed(agent.WAIT, world.Floor) {
+		t.Fatal("waiting on floor should not conceal")
+	}
+	if !Concealed(agent.HIDE, world.Floor) || !Concealed(agent.WAIT, world.Dark) {
+		t.Fatal("hiding or darkness should conceal")
+	}
+	if Reveals(agent.WAIT) || !Reveals(agent.OBSERVE) {
+		t.Fatal("only observing should reveal concealed entities")
+	}
+}
+
+func TestHear_NoiseFallsOffWithDistance(t *testing.T) {
+	from := world.Position{X: 10, Y: 10}
+	cue, ok := Hear(from, world.Position{X: 6, Y: 10}, agent.MOVE_E)
+	if !ok || cue.Direction != "west" || cue.Loudness != MoveNoise-4 {
+		t.Fatalf("footsteps cue = %+v ok=%v", cue, ok)
+	}
+	if _, ok := Hear(from, world.Position{X: 10, Y: 10 + MoveNoise}, agent.MOVE_N); ok {
+		t.Fatal("footsteps carried past their level")
+	}
+	if _, ok := Hear(from, world.Position{X: 11, Y: 10}, agent.HIDE); ok {
+		t.Fatal("hiding made noise")
+	}
+	if cue, ok := Hear(from, world.Position{X: 14, Y: 2}, agent.ATTACK); !ok || cue.Direction != "north" {
+		t.Fatalf("struggle cue = %+v ok=%v", cue, ok)
+	}
+}
+
+func TestBearing_EightWay(t *testing.T) {
+	o := world.Position{X: 0, Y: 0}
+	cases := map[world.Position]string{
+		{X: 0, Y: -3}: "north", {X: 3, Y: -3}: "northeast", {X: 5, Y: 1}: "east",
+		{X: 2, Y: 2}: "southeast", {X: -1, Y: 4}: "south", {X: -3, Y: 2}: "southwest",
+	}
+	for p, want := range cases {
+		if got := Bearing(o, p); got != want {
+			t.Fatalf("Bearing to %+v = %q, want %q", p, got, want)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package net
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+var connSeq atomic.Uint64
+
+// ConnLogger returns base annotated with a process-unique connection
+// number, so every record about one connection can be followed across
+// goroutines.
+func ConnLogger(base *slog.Logger) *slog.Logger {
+	return base.With("conn", connSeq.Add(1))
+}
@@ -0,0 +1,319 @@
+package persist
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+// AgentStore saves and loads agent records by agent id. Load of an unknown
+// id returns an error satisfying errors.Is(err, fs.ErrNotExist); a record
+// that exists but cannot be read is reported as a *SchemaError.
+type AgentStore interface {
+	Load(id string) (AgentRecord, error)
+	Save(id string, rec AgentRecord) error
+	List() ([]string, error)
+	Delete(id string) error
+}
+
+// BatchSaver is implemented by stores that can write several records as one
+// transaction.
+type BatchSaver interface {
+	SaveAll(recs map[string]AgentRecord) error
+}
+
+// SaveAll writes recs to store, as a single transaction when the store
+// supports it and record by record otherwise.
+func SaveAll(store AgentStore, recs map[string]AgentRecord) error {
+	if b, ok := store.(BatchSaver); ok {
+		return b.SaveAll(recs)
+	}
+	ids := make([]string, 0, len(recs))
+	for id := range recs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := store.Save(id, recs[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Store backends selectable by name.
+const (
+	StoreDir = "dir"
+	StoreLog = "log"
+)
+
+// OpenAgentStore opens the named backend under BaseDir: "dir" keeps one
+// directory per agent under agents/, "log" keeps every agent in agents.log.
+func OpenAgentStore(backend string) (AgentStore, error) {
+	switch backend {
+	case StoreDir:
+		return DefaultAgentStore(), nil
+	case StoreLog:
+		return OpenLogStore(filepath.Join(BaseDir(), "agents.log"))
+	}
+	return nil, fmt.Errorf("unknown agent store %q (want %s or %s)", backend, StoreDir, StoreLog)
+}
+
+// PositionRecord is a saved stage position.
+type PositionRecord struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// IntrospectionRecord is one captured introspection snapshot.
+type IntrospectionRecord struct {
+	Tick         int  `json:"tick"`
+	TotalBeliefs int  `json:"totalBeliefs"`
+	Certain      int  `json:"certain"`
+	Recent       int  `json:"recent"`
+	Fading       int  `json:"fading"`
+	Doubtful     int  `json:"doubtful"`
+	HasScars     bool `json:"hasScars"`
+	Forgotten    int  `json:"forgotten,omitempty"`
+}
+
+// AgentRecord is the saved state of one agent. Position is the last
+// position the runtime reported and Run the run the agent last took part
+// in; both are absent for agents that never joined a run. Memory and
+// Entities use the agent package's canonical encoding.
+type AgentRecord struct {
+	Run           string                `json:"run,omitempty"`
+	Position      *PositionRecord       `json:"position,omitempty"`
+	Energy        int                   `json:"energy"`
+	Memory        []agent.MemoryTile    `json:"memory"`
+	Entities      []agent.EntityMemory  `json:"entities,omitempty"`
+	Introspection []IntrospectionRecord `json:"introspection"`
+}
+
+// NewAgentRecord builds a record from an agent's state. pos is nil when the
+// agent is not on the stage.
+func NewAgentRecord(st agent.SaveState, pos *core.Position, run string) AgentRecord {
+	rec := AgentRecord{
+		Run:           run,
+		Energy:        st.Energy,
+		Memory:        append([]agent.MemoryTile{}, st.Memory...),
+		Entities:      append([]agent.EntityMemory(nil), st.Entities...),
+		Introspection: []IntrospectionRecord{},
+	}
+	if pos != nil {
+		rec.Position = &PositionRecord{X: pos.X, Y: pos.Y}
+	}
+	for _, s := range st.Introspection {
+		r := s.Report
+		rec.Introspection = append(rec.Introspection, IntrospectionRecord{
+			Tick:         s.Tick,
+			TotalBeliefs: r.TotalBeliefs,
+			Certain:      r.Certain,
+			Recent:       r.Recent,
+			Fading:       r.Fading,
+			Doubtful:     r.Doubtful,
+			HasScars:     r.HasScars,
+			Forgotten:    r.Forgotten,
+		})
+	}
+	return rec
+}
+
+// State returns the agent state held in rec.
+func (rec AgentRecord) State() agent.SaveState {
+	st := agent.SaveState{
+		Energy:   rec.Energy,
+		Memory:   append([]agent.MemoryTile(nil), rec.Memory...),
+		Entities: append([]agent.EntityMemory(nil), rec.Entities...),
+	}
+	for _, s := range rec.Introspection {
+		st.Introspection = append(st.Introspection, agent.IntrospectionSnapshot{
+			Tick: s.Tick,
+			Report: agent.IntrospectionReport{
+				TotalBeliefs: s.TotalBeliefs,
+				Certain:      s.Certain,
+				Recent:       s.Recent,
+				Fading:       s.Fading,
+				Doubtful:     s.Doubtful,
+				HasScars:     s.HasScars,
+				Forgotten:    s.Forgotten,
+			},
+		})
+	}
+	return st
+}
+
+// PositionValue returns the saved position, if any.
+func (rec AgentRecord) PositionValue() (core.Position, bool) {
+	if rec.Position == nil {
+		return core.Position{}, false
+	}
+	return core.Position{X: rec.Position.X, Y: rec.Position.Y}, true
+}
+
+// DirStore is the JSON directory layout: one directory per agent under Dir
+// holding agent.json. Directories are named by the agent id in unpadded
+// URL-safe base64, so every id is a single path component; stores written
+// with raw ids as names are migrated on first use (see dirlayout.go).
+type DirStore struct {
+	Dir string
+
+	mu       sync.Mutex
+	migrated bool
+}
+
+// NewDirStore returns a store rooted at dir.
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{Dir: dir}
+}
+
+// DefaultAgentStore returns the directory store under BaseDir.
+func DefaultAgentStore() *DirStore {
+	return NewDirStore(filepath.Join(BaseDir(), "agents"))
+}
+
+// AgentDir returns the directory holding agent id's saved state.
+func (s *DirStore) AgentDir(id string) string {
+	return filepath.Join(s.Dir, dirName(id))
+}
+
+// AgentPath returns the path of agent id's save document.
+func (s *DirStore) AgentPath(id string) string {
+	return filepath.Join(s.AgentDir(id), "agent.json")
+}
+
+// Save atomically writes agent id's record.
+func (s *DirStore) Save(id string, rec AgentRecord) error {
+	if id == "" {
+		return errEmptyID
+	}
+	if err := s.layout(true); err != nil {
+		return err
+	}
+	return WriteVersioned(s.AgentPath(id), KindAgent, rec)
+}
+
+// Load reads agent id's record. Agents saved before agent.json existed kept
+// energy in state.json and tiles in memory.json; those are read as a
+// version 0 document, migrated, and written to agent.json, leaving the old
+// files in place.
+func (s *DirStore) Load(id string) (AgentRecord, error) {
+	var rec AgentRecord
+	if id == "" {
+		return rec, fs.ErrNotExist
+	}
+	if err := s.layout(false); err != nil {
+		return rec, err
+	}
+	err := ReadVersioned(s.AgentPath(id), KindAgent, &rec)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return rec, err
+	}
+	doc, err := s.readLegacy(id)
+	if err != nil {
+		return rec, err
+	}
+	err = loadDoc(s.AgentPath(id), KindAgent, doc, nil, &rec)
+	return rec, err
+}
+
+// List returns the ids of all saved agents, sorted.
+func (s *DirStore) List() ([]string, error) {
+	if err := s.layout(false); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		id, ok := idFromDirName(e.Name())
+		if ok && holdsAgent(filepath.Join(s.Dir, e.Name())) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Delete removes everything saved for agent id. Deleting an unknown agent
+// is not an error.
+func (s *DirStore) Delete(id string) error {
+	if id == "" {
+		return nil
+	}
+	if err := s.layout(false); err != nil {
+		return err
+	}
+	return os.RemoveAll(s.AgentDir(id))
+}
+
+// readLegacy merges state.json and memory.json into one document.
+func (s *DirStore) readLegacy(id string) (map[string]any, error) {
+	doc := map[string]any{}
+	found := false
+	for _, f := range []struct{ name, key string }{
+		{"state.json", "energy"},
+		{"memory.json", "tiles"},
+	} {
+		path := filepath.Join(s.AgentDir(id), f.name)
+		b, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, &SchemaError{Path: path, Kind: KindAgent, Err: err}
+		}
+		part, err := decodeDoc(b)
+		if err != nil {
+			return nil, &SchemaError{Path: path, Kind: KindAgent, Err: err}
+		}
+		found = true
+		if val, ok := part[f.key]; ok {
+			doc[f.key] = val
+		}
+	}
+	if !found {
+		return nil, fs.ErrNotExist
+	}
+	return doc, nil
+}
+
+// legacyEnergy is the energy a version 0 agent had when state.json was
+// missing: the maximum at the time version 1 was introduced.
+const legacyEnergy = 100
+
+func init() {
+	// 0 -> 1: memory tiles move from "tiles" to "memory" and the document
+	// gains an (empty) introspection history.
+	RegisterMigration(KindAgent, 0, func(doc map[string]any) error {
+		if _, ok := doc["energy"]; !ok {
+			doc["energy"] = legacyEnergy
+		}
+		tiles, ok := doc["tiles"]
+		if !ok || tiles == nil {
+			tiles = []any{}
+		}
+		if _, ok := tiles.([]any); !ok {
+			return errors.New("tiles is not a list")
+		}
+		delete(doc, "tiles")
+		doc["memory"] = tiles
+		doc["introspection"] = []any{}
+		return nil
+	})
+}
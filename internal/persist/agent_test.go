@@ -0,0 +1,136 @@
+package persist
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+func TestDirStore_SaveLoadListDelete(t *testing.T) {
+	store := NewDirStore(t.TempDir())
+	var _ AgentStore = store
+
+	st := agent.SaveState{
+		Energy: 57,
+		Memory: []agent.MemoryTile{{
+			Tile:      core.TileView{Position: core.Position{X: 4, Y: 1}, Glyph: '#', Visible: true},
+			LastSeen:  9,
+			ScarLevel: 2,
+		}},
+		Entities: []agent.EntityMemory{{
+			ID:       "watcher",
+			Position: core.Position{X: 6, Y: 2},
+			LastSeen: 8,
+			Heading:  core.Position{X: -1},
+			Rumor:    true,
+		}},
+		Introspection: []agent.IntrospectionSnapshot{{Tick: 9, Report: agent.IntrospectionReport{TotalBeliefs: 1, Certain: 1, HasScars: true, Forgotten: 2}}},
+	}
+	pos := core.Position{X: 3, Y: 5}
+	for _, id := range []string{"b", "a"} {
+		if err := store.Save(id, NewAgentRecord(st, &pos, "run-1")); err != nil {
+			t.Fatalf("Save(%s): %v", id, err)
+		}
+	}
+
+	rec, err := store.Load("a")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(rec.State(), st) {
+		t.Fatalf("state round trip:\n got %+v\nwant %+v", rec.State(), st)
+	}
+	if p, ok := rec.PositionValue(); !ok || p != pos || rec.Run != "run-1" {
+		t.Fatalf("position/run = %+v %v %q", p, ok, rec.Run)
+	}
+
+	ids, err := store.List()
+	if err != nil || !reflect.DeepEqual(ids, []string{"a", "b"}) {
+		t.Fatalf("List = %v, %v", ids, err)
+	}
+	if err := store.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if ids, _ := store.List(); !reflect.DeepEqual(ids, []string{"b"}) {
+		t.Fatalf("List after delete = %v", ids)
+	}
+}
+
+func TestNewAgentRecord_IsReproducible(t *testing.T) {
+	encode := func(order []int) []byte {
+		h := agent.NewHuman("H")
+		for _, i := range order {
+			p := core.Position{X: i % 3, Y: i / 3}
+			h.Memory().SetMemoryTile(p, agent.MemoryTile{Tile: core.TileView{Position: p, Glyph: '.'}, LastSeen: i})
+		}
+		b, err := json.Marshal(NewAgentRecord(h.State(), nil, "run-1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	want := encode([]int{0, 1, 2, 3, 4, 5, 6, 7, 8})
+	for _, order := range [][]int{{8, 7, 6, 5, 4, 3, 2, 1, 0}, {4, 0, 8, 2, 6, 1, 7, 3, 5}} {
+		if got := encode(order); !bytes.Equal(got, want) {
+			t.Fatalf("record depends on insertion order:\n got %s\nwant %s", got, want)
+		}
+	}
+}
+
+func TestDirStore_IdsWithSlashesStayListed(t *testing.T) {
+	store := NewDirStore(filepath.Join(t.TempDir(), "agents"))
+	ids := []string{"../escape", "You", "k+/abc==", "k+/abc==/x"}
+	for i, id := range ids {
+		if err := store.Save(id, AgentRecord{Energy: i}); err != nil {
+			t.Fatalf("Save(%q): %v", id, err)
+		}
+	}
+	got, err := store.List()
+	if err != nil || !reflect.DeepEqual(got, ids) {
+		t.Fatalf("List() = %q, %v; want %q", got, err, ids)
+	}
+	if err := store.Delete("k+/abc=="); err != nil {
+		t.Fatal(err)
+	}
+	if rec, err := store.Load("k+/abc==/x"); err != nil || rec.Energy != 3 {
+		t.Fatalf("deleting one id touched another: %+v, %v", rec, err)
+	}
+	if err := store.Save("", AgentRecord{}); err == nil {
+		t.Fatal("saved an agent with an empty id")
+	}
+	if err := store.Delete(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(store.Dir); err != nil {
+		t.Fatalf("Delete of the empty id removed the store: %v", err)
+	}
+}
+
+func TestDirStore_MigratesRawIdDirectories(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "agents")
+	writeFile(t, filepath.Join(dir, "You", "agent.json"), `{"version": 1, "energy": 7, "memory": [], "introspection": []}`)
+	writeFile(t, filepath.Join(dir, "k+", "abc==", "agent.json"), `{"version": 1, "energy": 8, "memory": [], "introspection": []}`)
+
+	store := NewDirStore(dir)
+	ids, err := store.List()
+	if err != nil || !reflect.DeepEqual(ids, []string{"You", "k+/abc=="}) {
+		t.Fatalf("List() = %q, %v", ids, err)
+	}
+	if rec, err := store.Load("k+/abc=="); err != nil || rec.Energy != 8 {
+		t.Fatalf("Load of nested legacy agent = %+v, %v", rec, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir+".legacy", "You", "agent.json")); err != nil {
+		t.Fatalf("legacy store not kept: %v", err)
+	}
+
+	// A second store on the same directory sees the marker and leaves it be.
+	if ids, err := NewDirStore(dir).List(); err != nil || len(ids) != 2 {
+		t.Fatalf("List() after migration = %q, %v", ids, err)
+	}
+}
@@ -0,0 +1,192 @@
+package persist
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DirStore layout. Agent ids are arbitrary strings: server ids are standard
+// base64 public keys and usually contain '/'. Used as directory names they
+// nested those agents out of List's sight, so each directory is named by
+// its id in unpadded URL-safe base64 instead, and the file layoutFile marks
+// a store laid out that way.
+//
+// A store without the marker predates the encoding. On first use every
+// directory below Dir holding an agent document is taken as a legacy agent,
+// its id being the path relative to Dir. The agents are copied into a new
+// store at Dir+".new", which is then swapped into place, keeping the old
+// store as Dir+".legacy". A crash before the swap leaves the legacy store
+// untouched; a crash during it leaves a complete new store that the next
+// use moves into place.
+
+// layoutFile marks a DirStore whose directories are encoded ids.
+const layoutFile = ".layout"
+
+// errEmptyID rejects the empty agent id, whose directory would be Dir.
+var errEmptyID = errors.New("persist: empty agent id")
+
+// agentFiles are the documents whose presence makes a directory an agent's.
+var agentFiles = []string{"agent.json", "state.json", "memory.json"}
+
+// dirName returns the directory name for agent id.
+func dirName(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// idFromDirName reverses dirName. Names it did not produce are rejected.
+func idFromDirName(name string) (string, bool) {
+	b, err := base64.RawURLEncoding.Strict().DecodeString(name)
+	if err != nil || len(b) == 0 {
+		return "", false
+	}
+	return string(b), true
+}
+
+func holdsAgent(dir string) bool {
+	for _, name := range agentFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func hasMarker(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, layoutFile))
+	return err == nil
+}
+
+func writeMarker(dir string) error {
+	return os.WriteFile(filepath.Join(dir, layoutFile), []byte("base64url\n"), 0o644)
+}
+
+// layout makes sure the store uses encoded directory names, migrating a
+// legacy store first. A missing Dir is created only when create is set;
+// until then there is nothing to migrate.
+func (s *DirStore) layout(create bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.migrated {
+		return nil
+	}
+	next := s.Dir + ".new"
+	_, err := os.Stat(s.Dir)
+	switch {
+	case err == nil && hasMarker(s.Dir):
+	case err == nil:
+		if err := s.migrateLegacy(next); err != nil {
+			return err
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	case hasMarker(next):
+		// A migration stopped between its two renames.
+		if err := os.Rename(next, s.Dir); err != nil {
+			return err
+		}
+	case !create:
+		return nil
+	default:
+		if err := ensureDir(s.Dir); err != nil {
+			return err
+		}
+		if err := writeMarker(s.Dir); err != nil {
+			return err
+		}
+	}
+	s.migrated = true
+	return nil
+}
+
+// migrateLegacy rebuilds the legacy store at s.Dir with encoded names in
+// next and swaps it into place. A store holding no agents is just marked.
+func (s *DirStore) migrateLegacy(next string) error {
+	var ids []string
+	err := filepath.WalkDir(s.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != s.Dir && holdsAgent(path) {
+			rel, err := filepath.Rel(s.Dir, path)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return writeMarker(s.Dir)
+	}
+
+	legacy := s.Dir + ".legacy"
+	if _, err := os.Stat(legacy); err == nil {
+		return fmt.Errorf("cannot migrate %s: %s already exists", s.Dir, legacy)
+	}
+	if err := os.RemoveAll(next); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := copyAgentFiles(filepath.Join(s.Dir, filepath.FromSlash(id)), filepath.Join(next, dirName(id))); err != nil {
+			return err
+		}
+	}
+	if err := writeMarker(next); err != nil {
+		return err
+	}
+	syncDir(next)
+	if err := os.Rename(s.Dir, legacy); err != nil {
+		return err
+	}
+	if err := os.Rename(next, s.Dir); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(s.Dir))
+	logger().Info("renamed agent directories to encoded ids", "dir", s.Dir, "agents", len(ids), "legacy", legacy)
+	return nil
+}
+
+// copyAgentFiles copies the regular files directly in src to a new
+// directory dst, fsyncing each.
+func copyAgentFiles(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := ensureDir(dst); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(src, e.Name()))
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(filepath.Join(dst, e.Name()), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(b); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	syncDir(dst)
+	return nil
+}
@@ -0,0 +1,324 @@
+package persist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// LogStore keeps every agent in one append-only file. Each flush appends a
+// batch of put and delete entries followed by a commit entry, and fsyncs,
+// so a flush is applied entirely or not at all: on open, entries after the
+// last commit (a torn or interrupted write) are discarded. Records are
+// stored as versioned documents and migrated on load. When superseded
+// entries outnumber live ones the file is compacted by rewriting only the
+// live records and renaming the result into place.
+//
+// A flush that fails partway is truncated back to the last commit before
+// the error is returned, so a retried flush never lands after orphaned
+// entries.
+type LogStore struct {
+	path string
+
+	mu   sync.Mutex
+	docs map[string]json.RawMessage
+	dead int
+
+	// size is the length of the log up to and including its last commit.
+	size int64
+
+	// wrap, when set, wraps the file appends are written through. Tests
+	// use it to inject write failures.
+	wrap func(io.Writer) io.Writer
+}
+
+type logEntry struct {
+	Op  string          `json:"op"`
+	ID  string          `json:"id,omitempty"`
+	Doc json.RawMessage `json:"doc,omitempty"`
+	N   int             `json:"n,omitempty"`
+}
+
+const (
+	opPut    = "put"
+	opDelete = "del"
+	opCommit = "commit"
+)
+
+// minCompactDead is the number of superseded entries below which the log is
+// never compacted, so small stores are not rewritten on every flush.
+const minCompactDead = 64
+
+// OpenLogStore opens or creates the log at path and replays it.
+func OpenLogStore(path string) (*LogStore, error) {
+	s := &LogStore{path: path, docs: map[string]json.RawMessage{}}
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// replay rebuilds the in-memory index from the committed entries in the log
+// and truncates anything after the last commit.
+func (s *LogStore) replay() error {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var pending []logEntry
+	committed := 0 // byte offset just past the last commit entry
+	offset := 0
+	line := 0
+	for len(b[offset:]) > 0 {
+		line++
+		end := bytes.IndexByte(b[offset:], '\n')
+		if end < 0 {
+			break // torn final entry
+		}
+		raw := b[offset : offset+end]
+		offset += end + 1
+		var e logEntry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			if offset == len(b) {
+				break // torn final entry
+			}
+			return fmt.Errorf("%s:%d: %w", s.path, line, err)
+		}
+		if e.Op != opCommit {
+			pending = append(pending, e)
+			continue
+		}
+		if e.N != len(pending) {
+			return fmt.Errorf("%s:%d: commit of %d entries after %d", s.path, line, e.N, len(pending))
+		}
+		s.apply(pending)
+		pending = nil
+		committed = offset
+	}
+	s.size = int64(committed)
+	if committed < len(b) {
+		logger().Warn("discarding uncommitted log tail", "path", s.path, "bytes", len(b)-committed)
+		return os.Truncate(s.path, s.size)
+	}
+	return nil
+}
+
+func (s *LogStore) apply(batch []logEntry) {
+	for _, e := range batch {
+		if _, ok := s.docs[e.ID]; ok {
+			s.dead++
+		}
+		switch e.Op {
+		case opPut:
+			s.docs[e.ID] = e.Doc
+		case opDelete:
+			delete(s.docs, e.ID)
+			s.dead++
+		}
+	}
+}
+
+// Load returns the record for agent id.
+func (s *LogStore) Load(id string) (AgentRecord, error) {
+	s.mu.Lock()
+	raw, ok := s.docs[id]
+	s.mu.Unlock()
+	var rec AgentRecord
+	if !ok {
+		return rec, fs.ErrNotExist
+	}
+	doc, err := decodeDoc(raw)
+	if err != nil {
+		return rec, &SchemaError{Path: s.path + "#" + id, Kind: KindAgent, Err: err}
+	}
+	from, err := Migrate(KindAgent, doc)
+	if err != nil {
+		return rec, &SchemaError{Path: s.path + "#" + id, Kind: KindAgent, Version: from, Err: err}
+	}
+	b, err := json.Marshal(doc)
+	if err == nil {
+		err = json.Unmarshal(b, &rec)
+	}
+	if err != nil {
+		return rec, &SchemaError{Path: s.path + "#" + id, Kind: KindAgent, Version: from, Err: err}
+	}
+	return rec, nil
+}
+
+// Save writes agent id's record as a batch of one.
+func (s *LogStore) Save(id string, rec AgentRecord) error {
+	return s.SaveAll(map[string]AgentRecord{id: rec})
+}
+
+// SaveAll writes every record in recs as one committed batch.
+func (s *LogStore) SaveAll(recs map[string]AgentRecord) error {
+	ids := make([]string, 0, len(recs))
+	for id := range recs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	batch := make([]logEntry, 0, len(ids))
+	for _, id := range ids {
+		doc, err := versionedDoc(KindAgent, recs[id])
+		if err != nil {
+			return err
+		}
+		batch = append(batch, logEntry{Op: opPut, ID: id, Doc: doc})
+	}
+	return s.commit(batch)
+}
+
+// Delete removes agent id. Deleting an unknown agent is not an error.
+func (s *LogStore) Delete(id string) error {
+	s.mu.Lock()
+	_, ok := s.docs[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return s.commit([]logEntry{{Op: opDelete, ID: id}})
+}
+
+// List returns the ids of all saved agents, sorted.
+func (s *LogStore) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.docs))
+	for id := range s.docs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// commit appends batch and its commit entry, fsyncs, and applies the batch
+// to the index. It compacts afterwards when enough entries are dead. If the
+// append or the fsync fails the log is truncated back to its last commit
+// and the batch is not applied.
+func (s *LogStore) commit(batch []logEntry) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ensureDir(filepath.Dir(s.path)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	// Drop whatever an earlier failed flush could not truncate away.
+	if info, err := f.Stat(); err == nil && info.Size() > s.size {
+		if err := f.Truncate(s.size); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	var out io.Writer = f
+	if s.wrap != nil {
+		out = s.wrap(f)
+	}
+	if err := writeEntries(bufio.NewWriter(out), append(batch, logEntry{Op: opCommit, N: len(batch)})); err != nil {
+		return s.rollback(f, err)
+	}
+	if err := f.Sync(); err != nil {
+		return s.rollback(f, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return s.rollback(f, err)
+	}
+	// The batch is durable from here on, whatever Close reports.
+	s.size = info.Size()
+	s.apply(batch)
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if s.dead >= minCompactDead && s.dead > len(s.docs) {
+		return s.compact()
+	}
+	return nil
+}
+
+// rollback truncates f back to the last commit after a failed flush, closes
+// it and returns err. Should the truncation fail too, the next commit
+// retries it before appending.
+func (s *LogStore) rollback(f *os.File, err error) error {
+	if terr := f.Truncate(s.size); terr != nil {
+		logger().Error("cannot truncate failed flush", "path", s.path, "err", terr)
+	}
+	f.Close()
+	return err
+}
+
+// compact rewrites the log with one put per live record in a single batch.
+func (s *LogStore) compact() error {
+	ids := make([]string, 0, len(s.docs))
+	for id := range s.docs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	batch := make([]logEntry, 0, len(ids)+1)
+	for _, id := range ids {
+		batch = append(batch, logEntry{Op: opPut, ID: id, Doc: s.docs[id]})
+	}
+	batch = append(batch, logEntry{Op: opCommit, N: len(ids)})
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := writeEntries(bufio.NewWriter(f), batch); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(s.path))
+	s.size = info.Size()
+	logger().Info("compacted agent log", "path", s.path, "live", len(ids), "dropped", s.dead)
+	s.dead = 0
+	return nil
+}
+
+func writeEntries(w *bufio.Writer, entries []logEntry) error {
+	for _, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		if err := w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
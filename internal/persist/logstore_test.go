@@ -0,0 +1,189 @@
+package persist
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+func TestLogStore_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.log")
+	s, err := OpenLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var _ AgentStore = s
+	err = SaveAll(s, map[string]AgentRecord{
+		"a": {Energy: 10, Memory: []agent.MemoryTile{{Tile: core.TileView{Position: core.Position{X: 1, Y: 2}, Glyph: 35}}}},
+		"b": {Energy: 20},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("a", AgentRecord{Energy: 11, Position: &PositionRecord{X: 4, Y: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = OpenLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := s.Load("a")
+	if err != nil || rec.Energy != 11 || rec.Position == nil || rec.Position.X != 4 {
+		t.Fatalf("Load(a) = %+v, %v", rec, err)
+	}
+	if _, err := s.Load("b"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("deleted agent still loads: %v", err)
+	}
+	if ids, _ := s.List(); !reflect.DeepEqual(ids, []string{"a"}) {
+		t.Fatalf("List = %v", ids)
+	}
+}
+
+func TestLogStore_DiscardsUncommittedTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.log")
+	s, err := OpenLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("a", AgentRecord{Energy: 10}); err != nil {
+		t.Fatal(err)
+	}
+	good, _ := os.ReadFile(path)
+
+	// A flush interrupted after one put and midway through the next.
+	torn := string(good) + `{"op":"put","id":"a","doc":{"version":1,"energy":99}}` + "\n" + `{"op":"put","id":"c","do`
+	if err := os.WriteFile(path, []byte(torn), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err = OpenLogStore(path)
+	if err != nil {
+		t.Fatalf("reopen after torn write: %v", err)
+	}
+	if rec, err := s.Load("a"); err != nil || rec.Energy != 10 {
+		t.Fatalf("uncommitted put applied: %+v, %v", rec, err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != string(good) {
+		t.Fatalf("torn tail not truncated:\n%s", b)
+	}
+}
+
+func TestLogStore_CompactsSupersededEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.log")
+	s, err := OpenLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= minCompactDead; i++ {
+		if err := s.Save("a", AgentRecord{Energy: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, _ := os.ReadFile(path)
+	if lines := len(splitLines(b)); lines != 2 {
+		t.Fatalf("compacted log has %d lines, want put+commit", lines)
+	}
+	s, err = OpenLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec, _ := s.Load("a"); rec.Energy != minCompactDead {
+		t.Fatalf("energy after compaction = %d", rec.Energy)
+	}
+}
+
+func TestOpenAgentStore_SelectsBackend(t *testing.T) {
+	t.Setenv("NIGHTSHADE_DIR", t.TempDir())
+	if s, err := OpenAgentStore(StoreLog); err != nil {
+		t.Fatal(err)
+	} else if _, ok := s.(*LogStore); !ok {
+		t.Fatalf("log backend is %T", s)
+	}
+	if _, err := OpenAgentStore("sqlite"); err == nil {
+		t.Fatal("unknown backend accepted")
+	}
+}
+
+func splitLines(b []byte) []string {
+	out := []string{}
+	start := 0
+	for i, c := range b {
+		if c == '\n' {
+			out = append(out, string(b[start:i]))
+			start = i + 1
+		}
+	}
+	return out
+}
+
+// failingWriter passes through n bytes and then fails every write.
+type failingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (f *failingWriter) Write(b []byte) (int, error) {
+	if len(b) > f.n {
+		k, _ := f.w.Write(b[:f.n])
+		f.n = 0
+		return k, errors.New("disk full")
+	}
+	f.n -= len(b)
+	return f.w.Write(b)
+}
+
+func TestLogStore_FailedFlushIsRolledBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.log")
+	s, err := OpenLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("a", AgentRecord{Energy: 10}); err != nil {
+		t.Fatal(err)
+	}
+	good, _ := os.ReadFile(path)
+
+	// A batch well past bufio's buffer, so part of it reaches the file
+	// before the failure.
+	big := map[string]AgentRecord{}
+	for i := 0; i < 20; i++ {
+		rec := AgentRecord{Energy: i}
+		for x := 0; x < 20; x++ {
+			rec.Memory = append(rec.Memory, agent.MemoryTile{Tile: core.TileView{Position: core.Position{X: x, Y: i}, Glyph: '.'}})
+		}
+		big[string(rune('b'+i))] = rec
+	}
+	s.wrap = func(w io.Writer) io.Writer { return &failingWriter{w: w, n: 6000} }
+	if err := s.SaveAll(big); err == nil {
+		t.Fatal("flush through a failing writer succeeded")
+	}
+	if b, _ := os.ReadFile(path); string(b) != string(good) {
+		t.Fatalf("failed flush left %d bytes behind", len(b)-len(good))
+	}
+	if ids, _ := s.List(); len(ids) != 1 {
+		t.Fatalf("failed flush applied: %v", ids)
+	}
+
+	// The saver retries; the retry must land on a clean log.
+	s.wrap = nil
+	if err := s.SaveAll(big); err != nil {
+		t.Fatal(err)
+	}
+	s, err = OpenLogStore(path)
+	if err != nil {
+		t.Fatalf("reopen after failed flush: %v", err)
+	}
+	if ids, _ := s.List(); len(ids) != 21 {
+		t.Fatalf("reopened log holds %d agents, want 21", len(ids))
+	}
+}
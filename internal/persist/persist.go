@@ -2,6 +2,7 @@ package persist
 
 import (
 	"encoding/json"
+	"log/slog"
 	"os"
 	"path/filepath"
 )
@@ -17,6 +18,13 @@ func BaseDir() string {
 	return filepath.Join(home, ".nightshade")
 }
 
+// logger returns the logger for persistence events. The server configures
+// slog's default logger; persistence records are tagged with their
+// component.
+func logger() *slog.Logger {
+	return slog.Default().With("component", "persist")
+}
+
 func ensureDir(p string) error {
 	return os.MkdirAll(p, 0o755)
 }
@@ -61,15 +69,23 @@ func WriteJSONAtomic(path string, v interface{}, perm os.FileMode) error {
 	if err := os.Rename(tmp, path); err != nil {
 		return err
 	}
-	// Attempt to fsync the directory to ensure rename durability (best-effort)
-	dir := filepath.Dir(path)
-	if dfd, err := os.Open(dir); err == nil {
-		_ = dfd.Sync()
-		_ = dfd.Close()
-	}
+	syncDir(filepath.Dir(path))
 	return nil
 }
 
+// syncDir fsyncs dir so a rename into it survives a crash. It is
+// best-effort: failures are logged, not returned.
+func syncDir(dir string) {
+	dfd, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	if err := dfd.Sync(); err != nil {
+		logger().Warn("directory sync failed; rename may not survive a crash", "dir", dir, "err", err)
+	}
+	dfd.Close()
+}
+
 func ReadJSON(path string, v interface{}) error {
 	b, err := os.ReadFile(path)
 	if err != nil {
@@ -0,0 +1,19 @@
+package persist
+
+import "path/filepath"
+
+// RunsDir returns the directory holding final run results.
+func RunsDir() string {
+	return filepath.Join(BaseDir(), "runs")
+}
+
+// RunResultPath returns the path of the result file for runID.
+func RunResultPath(runID string) string {
+	return filepath.Join(RunsDir(), runID+".json")
+}
+
+// WriteRunResult atomically writes the final result record for a run.
+// Results are written once and never rewritten.
+func WriteRunResult(runID string, v interface{}) error {
+	return WriteVersioned(RunResultPath(runID), KindRun, v)
+}
@@ -0,0 +1,204 @@
+package persist
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Every persisted JSON document carries a top-level "version" field giving
+// the schema version of its kind. Documents written before versioning have
+// no such field and are treated as version 0. On load, a document older than
+// the current version is upgraded one step at a time by the registered
+// migrations and written back, keeping the original alongside as
+// <path>.v<N>.bak.
+
+// Document kinds.
+const (
+	KindAgent = "agent"
+	KindRun   = "run"
+)
+
+// currentVersions holds the schema version written for each kind.
+var currentVersions = map[string]int{
+	KindAgent: 1,
+	KindRun:   1,
+}
+
+// MigrateFunc upgrades doc in place from the version it was registered for
+// to the next one. It must not touch the "version" field.
+type MigrateFunc func(doc map[string]any) error
+
+var migrations = map[string]map[int]MigrateFunc{}
+
+// RegisterMigration registers fn as the upgrade of kind documents from
+// version from to from+1. Registering the same step twice panics.
+func RegisterMigration(kind string, from int, fn MigrateFunc) {
+	if migrations[kind] == nil {
+		migrations[kind] = map[int]MigrateFunc{}
+	}
+	if _, dup := migrations[kind][from]; dup {
+		panic(fmt.Sprintf("persist: duplicate %s migration from version %d", kind, from))
+	}
+	migrations[kind][from] = fn
+}
+
+// CurrentVersion returns the schema version written for kind.
+func CurrentVersion(kind string) int {
+	return currentVersions[kind]
+}
+
+// SchemaError reports a persisted document that exists but cannot be
+// loaded. The file is left untouched so nothing is lost.
+type SchemaError struct {
+	Path    string
+	Kind    string
+	Version int
+	Err     error
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s: %s document version %d: %v", e.Path, e.Kind, e.Version, e.Err)
+}
+
+func (e *SchemaError) Unwrap() error { return e.Err }
+
+// Migrate upgrades doc to the current version of kind and reports the
+// version it started at.
+func Migrate(kind string, doc map[string]any) (from int, err error) {
+	from, err = docVersion(doc)
+	if err != nil {
+		return 0, err
+	}
+	current, ok := currentVersions[kind]
+	if !ok {
+		return from, fmt.Errorf("unknown document kind %q", kind)
+	}
+	if from > current {
+		return from, fmt.Errorf("written by a newer version (this build reads up to %d)", current)
+	}
+	for v := from; v < current; v++ {
+		fn, ok := migrations[kind][v]
+		if !ok {
+			return from, fmt.Errorf("no migration from version %d", v)
+		}
+		if err := fn(doc); err != nil {
+			return from, fmt.Errorf("migrating from version %d: %w", v, err)
+		}
+	}
+	doc["version"] = current
+	return from, nil
+}
+
+func docVersion(doc map[string]any) (int, error) {
+	raw, ok := doc["version"]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("version is %T, not a number", raw)
+	}
+	v, err := n.Int64()
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid version %s", n)
+	}
+	return int(v), nil
+}
+
+func decodeDoc(b []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, errors.New("document is not a JSON object")
+	}
+	return doc, nil
+}
+
+// WriteVersioned atomically writes v, which must encode as a JSON object,
+// stamped with the current version of kind.
+func WriteVersioned(path, kind string, v any) error {
+	doc, err := stamp(kind, v)
+	if err != nil {
+		return err
+	}
+	return WriteJSONAtomic(path, doc, 0o644)
+}
+
+// versionedDoc returns the compact JSON encoding of v stamped with the
+// current version of kind.
+func versionedDoc(kind string, v any) (json.RawMessage, error) {
+	doc, err := stamp(kind, v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func stamp(kind string, v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeDoc(b)
+	if err != nil {
+		return nil, err
+	}
+	doc["version"] = CurrentVersion(kind)
+	return doc, nil
+}
+
+// ReadVersioned loads the kind document at path into v, upgrading it first
+// if it is older than the current version. A missing file returns an error
+// satisfying errors.Is(err, fs.ErrNotExist); any other failure is a
+// *SchemaError.
+func ReadVersioned(path, kind string, v any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return &SchemaError{Path: path, Kind: kind, Err: err}
+	}
+	doc, err := decodeDoc(b)
+	if err != nil {
+		return &SchemaError{Path: path, Kind: kind, Err: err}
+	}
+	return loadDoc(path, kind, doc, b, v)
+}
+
+// loadDoc migrates doc, writes the upgrade back to path keeping orig (when
+// non-nil) as a backup, and decodes the result into v.
+func loadDoc(path, kind string, doc map[string]any, orig []byte, v any) error {
+	from, err := Migrate(kind, doc)
+	if err != nil {
+		return &SchemaError{Path: path, Kind: kind, Version: from, Err: err}
+	}
+	if from < CurrentVersion(kind) {
+		if orig != nil {
+			backup := fmt.Sprintf("%s.v%d.bak", path, from)
+			if err := os.WriteFile(backup, orig, 0o644); err != nil {
+				return &SchemaError{Path: path, Kind: kind, Version: from, Err: err}
+			}
+		}
+		if err := WriteJSONAtomic(path, doc, 0o644); err != nil {
+			return &SchemaError{Path: path, Kind: kind, Version: from, Err: err}
+		}
+		logger().Info("migrated document", "path", path, "kind", kind, "from", from, "to", CurrentVersion(kind))
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return &SchemaError{Path: path, Kind: kind, Version: from, Err: err}
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return &SchemaError{Path: path, Kind: kind, Version: from, Err: err}
+	}
+	return nil
+}
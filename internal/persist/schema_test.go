@@ -0,0 +1,100 @@
+package persist
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testAgentDoc struct {
+	Version       int              `json:"version"`
+	Energy        int              `json:"energy"`
+	Memory        []map[string]int `json:"memory"`
+	Introspection []any            `json:"introspection"`
+}
+
+func writeFile(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDirStore_MigratesLegacyFiles(t *testing.T) {
+	store := NewDirStore(t.TempDir())
+	writeFile(t, filepath.Join(store.Dir, "a", "state.json"), `{"energy": 42}`)
+	writeFile(t, filepath.Join(store.Dir, "a", "memory.json"), `{"tiles": [{"x": 1, "y": 2, "glyph": 35, "lastSeen": 3, "scarLevel": 1}]}`)
+
+	rec, err := store.Load("a")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if rec.Energy != 42 || len(rec.Memory) != 1 || rec.Memory[0].Tile.Glyph != 35 || rec.Memory[0].ScarLevel != 1 {
+		t.Fatalf("migrated record = %+v", rec)
+	}
+	// The upgrade is written back and read directly next time.
+	var doc testAgentDoc
+	if err := ReadVersioned(store.AgentPath("a"), KindAgent, &doc); err != nil || doc.Version != 1 || doc.Energy != 42 || doc.Introspection == nil {
+		t.Fatalf("upgraded agent.json = %+v, %v", doc, err)
+	}
+}
+
+func TestDirStore_MissingIsNotExist(t *testing.T) {
+	store := NewDirStore(t.TempDir())
+	if _, err := store.Load("nobody"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Load of unknown agent = %v", err)
+	}
+}
+
+func TestReadVersioned_UpgradeKeepsBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.json")
+	writeFile(t, path, `{"energy": 7}`)
+	var doc testAgentDoc
+	if err := ReadVersioned(path, KindAgent, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(path + ".v0.bak"); err != nil || string(b) != `{"energy": 7}` {
+		t.Fatalf("backup = %q, %v", b, err)
+	}
+}
+
+func TestReadVersioned_UnrecoverableFilesAreReported(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]string{
+		"corrupt.json":  `{"energy": `,
+		"future.json":   `{"version": 99, "energy": 1}`,
+		"badtiles.json": `{"tiles": "oops"}`,
+	}
+	for name, body := range cases {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, body)
+		var doc testAgentDoc
+		err := ReadVersioned(path, KindAgent, &doc)
+		var se *SchemaError
+		if !errors.As(err, &se) || se.Path != path {
+			t.Fatalf("%s: error = %v, want *SchemaError", name, err)
+		}
+		if b, _ := os.ReadFile(path); string(b) != body {
+			t.Fatalf("%s: file modified to %q", name, b)
+		}
+	}
+}
+
+func TestWriteRunResult_IsVersioned(t *testing.T) {
+	t.Setenv("NIGHTSHADE_DIR", t.TempDir())
+	if err := WriteRunResult("r1", map[string]string{"condition": "survival"}); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Version   int    `json:"version"`
+		Condition string `json:"condition"`
+	}
+	if err := ReadJSON(RunResultPath("r1"), &got); err != nil || got.Version != CurrentVersion(KindRun) || got.Condition != "survival" {
+		t.Fatalf("run result = %+v, %v", got, err)
+	}
+}
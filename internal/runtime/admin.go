@@ -0,0 +1,117 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/game"
+)
+
+// Operator controls. These change a run from outside its rules, so they are
+// meant for the server's admin channel and tests, never for agents. Like
+// everything else on Runtime they must be called from the goroutine that
+// owns it (through Loop.Do once a loop is running).
+
+// ErrRunEnded is returned by controls that need a run still in progress.
+var ErrRunEnded = errors.New("run has ended")
+
+// EntityStatus is the live state of one active entity.
+type EntityStatus struct {
+	ID       string        `json:"id"`
+	Position core.Position `json:"position"`
+	Energy   int           `json:"energy"`
+	// Profile names the entity's cognition profile, when it has one.
+	Profile string `json:"profile,omitempty"`
+}
+
+// Status lists the active entities in registration order.
+func (r *Runtime) Status() []EntityStatus {
+	out := make([]EntityStatus, 0, len(r.agents))
+	for _, a := range r.agents {
+		res := r.entityResult(a, "")
+		st := EntityStatus{ID: res.ID, Position: res.Position, Energy: res.Energy}
+		if p, ok := a.(agent.Profiled); ok {
+			st.Profile = p.Profile().Name
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// Kick removes entity id from the run with OutcomeKicked. It reports
+// whether id was active.
+func (r *Runtime) Kick(id string) bool {
+	if r.state == RunEnded {
+		return false
+	}
+	for _, a := range r.agents {
+		if a.ID() == id {
+			r.remove(id, OutcomeKicked)
+			return true
+		}
+	}
+	return false
+}
+
+// Spawn adds a to the run, placed by the configured spawn strategy, and
+// returns where it landed. The entity joins the roster and takes part from
+// the next tick.
+func (r *Runtime) Spawn(a agent.Agent) (core.Position, error) {
+	if r.state == RunEnded {
+		return core.Position{}, ErrRunEnded
+	}
+	id := a.ID()
+	for _, e := range r.roster {
+		if e.ID() == id {
+			return core.Position{}, fmt.Errorf("entity %q already took part in this run", id)
+		}
+	}
+	r.cfg.Profiles.Apply(a)
+	placeAgents(r.world, []string{id}, r.cfg.Spawn, nil, r.rng)
+	pos, ok := r.world.PositionOf(id)
+	if !ok {
+		return core.Position{}, errors.New("no free cell to spawn on")
+	}
+	r.agents = append(r.agents, a)
+	r.roster = append(r.roster, a)
+	r.log().Info("entity spawned", "agent", id, "x", pos.X, "y", pos.Y)
+	return core.Position{X: pos.X, Y: pos.Y}, nil
+}
+
+// WorldEvent names a world change an operator can trigger.
+type WorldEvent string
+
+const (
+	// EventOpenExits opens every exit now.
+	EventOpenExits WorldEvent = "open-exits"
+	// EventCloseExits closes every exit now.
+	EventCloseExits WorldEvent = "close-exits"
+	// EventPlaceExit places one more closed exit on a free cell.
+	EventPlaceExit WorldEvent = "place-exit"
+)
+
+// Trigger applies ev to the world. Forced exits then follow their usual
+// schedule from the current tick.
+func (r *Runtime) Trigger(ev WorldEvent) error {
+	if r.state == RunEnded {
+		return ErrRunEnded
+	}
+	switch ev {
+	case EventOpenExits:
+		game.ForceExits(r.world, true, r.tick, r.rng, game.DefaultExitTiming)
+	case EventCloseExits:
+		game.ForceExits(r.world, false, r.tick, r.rng, game.DefaultExitTiming)
+	case EventPlaceExit:
+		before := len(r.world.Exits())
+		r.PlaceExits(1)
+		if len(r.world.Exits()) == before {
+			return errors.New("no free cell for an exit")
+		}
+	default:
+		return fmt.Errorf("unknown world event %q (want %s, %s or %s)", ev, EventOpenExits, EventCloseExits, EventPlaceExit)
+	}
+	r.log().Info("world event", "event", ev)
+	return nil
+}
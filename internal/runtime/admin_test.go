@@ -0,0 +1,118 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+func TestAdmin_StatusListsActiveEntities(t *testing.T) {
+	rt := New([]agent.Agent{agent.NewScripted("A"), &simpleAgent{id: "B", act: agent.WAIT}})
+	rt.TickOnce()
+	st := rt.Status()
+	if len(st) != 2 || st[0].ID != "A" || st[1].ID != "B" {
+		t.Fatalf("status = %+v", st)
+	}
+	if st[0].Position != (core.Position{X: 1, Y: 0}) || st[0].Energy != agent.MaxEnergy-agent.MoveEnergyCost {
+		t.Fatalf("status of A = %+v", st[0])
+	}
+}
+
+func TestAdmin_KickRecordsOutcome(t *testing.T) {
+	rt := New([]agent.Agent{&simpleAgent{id: "A", act: agent.WAIT}, &simpleAgent{id: "B", act: agent.WAIT}})
+	rt.AddWinCondition(Survival{})
+	rt.TickOnce()
+	if !rt.Kick("A") {
+		t.Fatal("kick of an active entity reported false")
+	}
+	if rt.Kick("A") || rt.Kick("nobody") {
+		t.Fatal("kick of an inactive entity reported true")
+	}
+	if _, ok := rt.PositionOf("A"); ok {
+		t.Fatal("kicked entity still on the stage")
+	}
+	rt.TickOnce()
+	res, ok := rt.Result()
+	if !ok || res.Entities[0].Outcome != OutcomeKicked || res.Entities[0].Success {
+		t.Fatalf("result = %+v", res)
+	}
+}
+
+func TestAdmin_SpawnJoinsRun(t *testing.T) {
+	rt := New([]agent.Agent{&simpleAgent{id: "A", act: agent.WAIT}})
+	rt.TickOnce()
+	pos, err := rt.Spawn(agent.NewOscillating("npc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos != (core.Position{X: 1, Y: 0}) {
+		t.Fatalf("spawned at %+v", pos)
+	}
+	if _, err := rt.Spawn(agent.NewOscillating("npc")); err == nil {
+		t.Fatal("spawning a duplicate id succeeded")
+	}
+	decisions := rt.TickOnce()
+	if _, ok := decisions["npc"]; !ok {
+		t.Fatal("spawned entity did not decide")
+	}
+	rt.End("test")
+	if res, _ := rt.Result(); len(res.Entities) != 2 {
+		t.Fatalf("result entities = %+v", res.Entities)
+	}
+	if _, err := rt.Spawn(agent.NewOscillating("late")); err != ErrRunEnded {
+		t.Fatalf("spawn after end: %v", err)
+	}
+}
+
+func TestAdmin_TriggerExits(t *testing.T) {
+	rt := New([]agent.Agent{&simpleAgent{id: "A", act: agent.WAIT}})
+	if err := rt.Trigger(EventPlaceExit); err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.Trigger(EventOpenExits); err != nil {
+		t.Fatal(err)
+	}
+	exits := rt.Exits()
+	if len(exits) != 1 || !exits[0].Open {
+		t.Fatalf("exits after open = %+v", exits)
+	}
+	if err := rt.Trigger(EventCloseExits); err != nil {
+		t.Fatal(err)
+	}
+	if rt.Exits()[0].Open {
+		t.Fatal("exit still open after close")
+	}
+	if err := rt.Trigger("flood"); err == nil {
+		t.Fatal("unknown event accepted")
+	}
+}
+
+func TestAdmin_ProfilesFollowEntitiesIntoTheRun(t *testing.T) {
+	ps, err := agent.ParseProfiles(strings.NewReader(`{
+		"profiles": {"heavy": {"moveCost": 4}, "calm": {}},
+		"agents": {"S": "calm"},
+		"default": "heavy"
+	}`), "test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	cfg.Profiles = ps
+	rt, err := NewWithConfig([]agent.Agent{agent.NewScripted("A")}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.Spawn(agent.NewScripted("S")); err != nil {
+		t.Fatal(err)
+	}
+	rt.TickOnce()
+	st := rt.Status()
+	if len(st) != 2 || st[0].Profile != "heavy" || st[1].Profile != "calm" {
+		t.Fatalf("status = %+v", st)
+	}
+	if st[0].Energy != agent.MaxEnergy-4 || st[1].Energy != agent.MaxEnergy-agent.MoveEnergyCost {
+		t.Fatalf("energies = %d, %d", st[0].Energy, st[1].Energy)
+	}
+}
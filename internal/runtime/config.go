@@ -0,0 +1,188 @@
+package runtime
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/util"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+// SpawnStrategy decides where entities are placed when a run is built.
+type SpawnStrategy string
+
+const (
+	// SpawnPoints uses the world's spawn points in order, then falls back to
+	// SpawnRow for any entities left over.
+	SpawnPoints SpawnStrategy = "points"
+	// SpawnRow places entities on the first free passable cells in
+	// row-major order. On an empty world this lines them up along the top
+	// row.
+	SpawnRow SpawnStrategy = "row"
+	// SpawnScatter places entities on random free passable cells drawn from
+	// the seeded RNG, keeping them apart where the stage allows.
+	SpawnScatter SpawnStrategy = "scatter"
+)
+
+// ParseSpawnStrategy converts a strategy name into a SpawnStrategy.
+func ParseSpawnStrategy(s string) (SpawnStrategy, error) {
+	switch SpawnStrategy(s) {
+	case SpawnPoints, SpawnRow, SpawnScatter:
+		return SpawnStrategy(s), nil
+	}
+	return "", fmt.Errorf("unknown spawn strategy %q", s)
+}
+
+// Config describes how a run is constructed. The zero value is not useful;
+// start from DefaultConfig.
+type Config struct {
+	// World, when set, is used as-is and Width, Height, Layout and Gen are
+	// ignored. Use it for stages loaded from map files.
+	World *world.World
+
+	Width  int
+	Height int
+	Layout world.Layout
+	Gen    world.GenOptions
+
+	Spawn SpawnStrategy
+	// Positions restores entities to saved positions, keyed by entity id.
+	// An entry is honored only when its cell is free; other entities are
+	// placed by Spawn.
+	Positions map[string]core.Position
+
+	VisibilityRadius int
+	TickRate         time.Duration
+	InputTimeout     time.Duration
+	Seed             uint64
+
+	// Metrics receives the runtime's metrics. When nil the runtime keeps
+	// its own registry, available from Runtime.Metrics.
+	Metrics *Metrics
+
+	// Profiles assigns cognition profiles to entities as they join the run,
+	// including those added by Spawn. When nil entities keep the profile
+	// they were built with.
+	Profiles *agent.Profiles
+
+	// Logger receives run events. Every record carries the tick; records
+	// about one entity also carry its id as "agent". Nil uses slog.Default.
+	Logger *slog.Logger
+}
+
+// DefaultConfig returns the configuration used by New: an empty 80x25
+// stage with entities placed along the top row.
+func DefaultConfig() Config {
+	return Config{
+		Width:            world.Width,
+		Height:           world.Height,
+		Layout:           world.LayoutEmpty,
+		Spawn:            SpawnPoints,
+		VisibilityRadius: defaultVisibilityRadius,
+		TickRate:         200 * time.Millisecond,
+		InputTimeout:     200 * time.Millisecond,
+		Seed:             defaultSeed,
+	}
+}
+
+// minScatterDistance is the Manhattan distance SpawnScatter tries to keep
+// between entities.
+const minScatterDistance = 4
+
+// placeAgents positions each agent according to strategy. Agents with a
+// free restored position are placed there first so spawning cannot take
+// their cell. Entities never share a cell and never start inside impassable
+// terrain; agents that cannot be placed (a full stage) are left without a
+// position.
+func placeAgents(w *world.World, ids []string, strategy SpawnStrategy, restore map[string]core.Position, rng *util.Rand) {
+	restored := map[string]bool{}
+	for _, id := range ids {
+		if p, ok := restore[id]; ok && free(w, world.Position{X: p.X, Y: p.Y}) {
+			w.SetPosition(id, world.Position{X: p.X, Y: p.Y})
+			restored[id] = true
+		}
+	}
+	for _, id := range ids {
+		if restored[id] {
+			continue
+		}
+		var pos world.Position
+		var ok bool
+		switch strategy {
+		case SpawnScatter:
+			pos, ok = scatterSpawn(w, rng)
+		case SpawnRow:
+			pos, ok = rowSpawn(w)
+		default:
+			pos, ok = pointSpawn(w)
+		}
+		if ok {
+			w.SetPosition(id, pos)
+		}
+	}
+}
+
+func free(w *world.World, p world.Position) bool {
+	return w.Passable(p) && !w.Occupied(p)
+}
+
+// pointSpawn returns the first unoccupied spawn point, falling back to
+// rowSpawn.
+func pointSpawn(w *world.World) (world.Position, bool) {
+	for _, p := range w.Spawns() {
+		if free(w, p) {
+			return p, true
+		}
+	}
+	return rowSpawn(w)
+}
+
+// rowSpawn returns the first unoccupied passable cell in row-major order.
+func rowSpawn(w *world.World) (world.Position, bool) {
+	for y := 0; y < w.Height(); y++ {
+		for x := 0; x < w.Width(); x++ {
+			p := world.Position{X: x, Y: y}
+			if free(w, p) {
+				return p, true
+			}
+		}
+	}
+	return world.Position{}, false
+}
+
+// scatterSpawn draws random cells until it finds a free one at least
+// minScatterDistance from every placed entity. If the stage is too crowded
+// it settles for any free cell, and finally for rowSpawn.
+func scatterSpawn(w *world.World, rng *util.Rand) (world.Position, bool) {
+	tries := w.Width() * w.Height()
+	var fallback *world.Position
+	for i := 0; i < tries; i++ {
+		p := world.Position{X: rng.Intn(w.Width()), Y: rng.Intn(w.Height())}
+		if !free(w, p) {
+			continue
+		}
+		if farFromEntities(w, p) {
+			return p, true
+		}
+		if fallback == nil {
+			fallback = &p
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return rowSpawn(w)
+}
+
+func farFromEntities(w *world.World, p world.Position) bool {
+	for _, id := range w.EntityIDs() {
+		q, _ := w.PositionOf(id)
+		if util.Abs(p.X-q.X)+util.Abs(p.Y-q.Y) < minScatterDistance {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/util"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+func waiters(ids ...string) []agent.Agent {
+	out := []agent.Agent{}
+	for _, id := range ids {
+		out = append(out, &simpleAgent{id: id, act: agent.WAIT})
+	}
+	return out
+}
+
+func positions(rt *Runtime, ids ...string) []world.Position {
+	out := []world.Position{}
+	for _, id := range ids {
+		p, _ := rt.world.PositionOf(id)
+		out = append(out, p)
+	}
+	return out
+}
+
+func TestNewWithConfig_WorldSizeAndRadius(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Width, cfg.Height = 30, 12
+	cfg.VisibilityRadius = 1
+	rt, err := NewWithConfig(waiters("A"), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt.world.Width() != 30 || rt.world.Height() != 12 {
+		t.Fatalf("world is %dx%d, want 30x12", rt.world.Width(), rt.world.Height())
+	}
+	rt.world.SetPosition("A", world.Position{X: 5, Y: 5})
+	snap, _ := rt.SnapshotForDebug("A")
+	if len(snap.Visible) != 9 {
+		t.Fatalf("radius 1 should reveal 9 tiles, got %d", len(snap.Visible))
+	}
+}
+
+func TestNewWithConfig_RejectsBadConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Width = 0
+	if _, err := NewWithConfig(waiters("A"), cfg); err == nil {
+		t.Fatal("expected error for zero width")
+	}
+	cfg = DefaultConfig()
+	cfg.Layout = "maze"
+	if _, err := NewWithConfig(waiters("A"), cfg); err == nil {
+		t.Fatal("expected error for unknown layout")
+	}
+	cfg = DefaultConfig()
+	cfg.TickRate = 0
+	if _, err := NewWithConfig(waiters("A"), cfg); err == nil {
+		t.Fatal("expected error for zero tick rate")
+	}
+	cfg = DefaultConfig()
+	cfg.InputTimeout = -time.Millisecond
+	if _, err := NewWithConfig(waiters("A"), cfg); err == nil {
+		t.Fatal("expected error for negative input timeout")
+	}
+}
+
+func TestNewWithConfig_DefaultMatchesNew(t *testing.T) {
+	rt, err := NewWithConfig(waiters("A", "B", "C"), DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []world.Position{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}
+	if got := positions(rt, "A", "B", "C"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("positions = %+v, want %+v", got, want)
+	}
+}
+
+func TestSpawnScatter_DeterministicAndApart(t *testing.T) {
+	build := func() *Runtime {
+		cfg := DefaultConfig()
+		cfg.Layout = world.LayoutCaves
+		cfg.Spawn = SpawnScatter
+		cfg.Seed = 11
+		rt, err := NewWithConfig(waiters("A", "B", "C", "D"), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rt
+	}
+	a, b := build(), build()
+	pa, pb := positions(a, "A", "B", "C", "D"), positions(b, "A", "B", "C", "D")
+	if !reflect.DeepEqual(pa, pb) {
+		t.Fatalf("scatter differs for same seed: %+v vs %+v", pa, pb)
+	}
+	for i, p := range pa {
+		if !a.world.Passable(p) {
+			t.Fatalf("entity %d spawned in a wall at %+v", i, p)
+		}
+		for j, q := range pa[i+1:] {
+			if d := util.Abs(p.X-q.X) + util.Abs(p.Y-q.Y); d < minScatterDistance {
+				t.Fatalf("entities %d and %d spawned %d apart", i, i+1+j, d)
+			}
+		}
+	}
+}
+
+func TestSpawnRow_SkipsWallsAndOccupiedCells(t *testing.T) {
+	w := world.New(3, 2)
+	w.SetTerrain(world.Position{X: 1, Y: 0}, world.Wall)
+	w.AddSpawn(world.Position{X: 2, Y: 1}) // ignored by SpawnRow
+	cfg := DefaultConfig()
+	cfg.World = w
+	cfg.Spawn = SpawnRow
+	rt, err := NewWithConfig(waiters("A", "B", "C"), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []world.Position{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}}
+	if got := positions(rt, "A", "B", "C"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("positions = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewWithConfig_RestoresSavedPositions(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Positions = map[string]core.Position{
+		"B": {X: 0, Y: 0},   // free: restored, and A spawns elsewhere
+		"C": {X: -1, Y: 40}, // off the stage: placed normally
+	}
+	rt, err := NewWithConfig(waiters("A", "B", "C"), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := positions(rt, "A", "B", "C")
+	want := []world.Position{{X: 1, Y: 0}, {X: 0, Y: 0}, {X: 2, Y: 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("positions = %+v, want %+v", got, want)
+	}
+	if p, ok := rt.PositionOf("B"); !ok || p != (core.Position{X: 0, Y: 0}) {
+		t.Fatalf("PositionOf(B) = %+v, %v", p, ok)
+	}
+}
+
+func TestSnapshotSeed_FollowsRunSeedEntityAndTick(t *testing.T) {
+	seeds := func(seed uint64) [3]uint64 {
+		cfg := DefaultConfig()
+		cfg.Seed = seed
+		rt, err := NewWithConfig(waiters("A", "B"), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, _ := rt.SnapshotForDebug("A")
+		b, _ := rt.SnapshotForDebug("B")
+		rt.TickOnce()
+		a2, _ := rt.SnapshotForDebug("A")
+		return [3]uint64{a.Seed, b.Seed, a2.Seed}
+	}
+	got := seeds(7)
+	if got[0] == got[1] || got[0] == got[2] {
+		t.Fatalf("seeds repeat across entities or ticks: %v", got)
+	}
+	if again := seeds(7); again != got {
+		t.Fatalf("same run seed gave %v then %v", got, again)
+	}
+	if other := seeds(8); other == got {
+		t.Fatalf("different run seeds gave the same seeds %v", got)
+	}
+}
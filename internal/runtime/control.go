@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/game"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+// AddZone registers a named control zone covering the inclusive rectangle
+// min..max.
+func (r *Runtime) AddZone(name string, min, max core.Position) {
+	r.world.AddZone(world.Zone{
+		Name: name,
+		Min:  world.Position{X: min.X, Y: min.Y},
+		Max:  world.Position{X: max.X, Y: max.Y},
+	})
+}
+
+// ZoneNames returns the names of all control zones in registration order.
+func (r *Runtime) ZoneNames() []string {
+	names := []string{}
+	for _, z := range r.world.Zones() {
+		names = append(names, z.Name)
+	}
+	return names
+}
+
+// ZoneCenter returns the center of the named zone so scripted agents can be
+// given it as a goal.
+func (r *Runtime) ZoneCenter(name string) (core.Position, bool) {
+	for _, z := range r.world.Zones() {
+		if z.Name == name {
+			c := z.Center()
+			return core.Position{X: c.X, Y: c.Y}, true
+		}
+	}
+	return core.Position{}, false
+}
+
+// ZoneHolders returns the entity holding each zone after the last resolved
+// tick ("" for nobody). This is a debug accessor like MarkerPosition.
+func (r *Runtime) ZoneHolders() map[string]string {
+	out := make(map[string]string, len(r.holders))
+	for zone, id := range r.holders {
+		out[zone] = id
+	}
+	return out
+}
+
+// ZoneInfluence returns cumulative influence per zone and entity: the number
+// of ticks each entity has held each zone. This is a debug accessor.
+func (r *Runtime) ZoneInfluence() map[string]map[string]int {
+	out := make(map[string]map[string]int, len(r.influence))
+	for zone, scores := range r.influence {
+		cp := make(map[string]int, len(scores))
+		for id, n := range scores {
+			cp[id] = n
+		}
+		out[zone] = cp
+	}
+	return out
+}
+
+// accountZones records which entity holds each zone this tick and credits
+// the holder with one tick of influence.
+func (r *Runtime) accountZones() {
+	for _, z := range r.world.Zones() {
+		holder := game.ZoneHolder(r.world, z)
+		r.holders[z.Name] = holder
+		if holder == "" {
+			continue
+		}
+		if r.influence[z.Name] == nil {
+			r.influence[z.Name] = make(map[string]int)
+		}
+		r.influence[z.Name][holder]++
+	}
+}
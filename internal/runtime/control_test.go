@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+func TestControl_InfluenceAccumulatesForHolder(t *testing.T) {
+	a := &simpleAgent{id: "A", act: agent.WAIT}
+	b := &simpleAgent{id: "B", act: agent.WAIT}
+	rt := New([]agent.Agent{a, b})
+	rt.AddZone("core", core.Position{X: 10, Y: 10}, core.Position{X: 12, Y: 12})
+	rt.world.SetPosition("A", world.Position{X: 11, Y: 11})
+
+	rt.TickOnce()
+	rt.TickOnce()
+	if h := rt.ZoneHolders()["core"]; h != "A" {
+		t.Fatalf("holder = %q, want A", h)
+	}
+
+	// B contests the zone: nobody holds it and influence stops growing.
+	rt.world.SetPosition("B", world.Position{X: 10, Y: 10})
+	rt.TickOnce()
+	if h := rt.ZoneHolders()["core"]; h != "" {
+		t.Fatalf("contested zone held by %q", h)
+	}
+	inf := rt.ZoneInfluence()
+	if inf["core"]["A"] != 2 || inf["core"]["B"] != 0 {
+		t.Fatalf("unexpected influence: %v", inf)
+	}
+
+	// Returned maps are copies.
+	inf["core"]["A"] = 99
+	if rt.ZoneInfluence()["core"]["A"] != 2 {
+		t.Fatal("ZoneInfluence exposed internal state")
+	}
+}
+
+func TestControl_SeekerWinsZone(t *testing.T) {
+	seeker := agent.NewSeeker("S", core.Position{})
+	rt := New([]agent.Agent{&simpleAgent{id: "P", act: agent.WAIT}, seeker})
+	rt.AddZone("core", core.Position{X: 3, Y: 0}, core.Position{X: 3, Y: 0})
+	goal, ok := rt.ZoneCenter("core")
+	if !ok {
+		t.Fatal("missing zone center")
+	}
+	seeker.SetGoal(goal)
+	rt.AddWinCondition(Control{Ticks: 3})
+	rt.AddWinCondition(TickLimit{Ticks: 20})
+
+	for rt.State() != RunEnded {
+		rt.TickOnce()
+	}
+	res, _ := rt.Result()
+	if res.Condition != "control" {
+		t.Fatalf("run ended by %q, want control", res.Condition)
+	}
+	if res.Entities[1].ID != "S" || !res.Entities[1].Success || res.Entities[0].Success {
+		t.Fatalf("unexpected winners: %+v", res.Entities)
+	}
+}
+
+func TestVisibleTiles_ShowZoneGlyph(t *testing.T) {
+	rt := New([]agent.Agent{&simpleAgent{id: "A", act: agent.WAIT}})
+	rt.AddZone("core", core.Position{X: 1, Y: 1}, core.Position{X: 1, Y: 1})
+	snap, _ := rt.SnapshotForDebug("A")
+	for _, tv := range snap.Visible {
+		if tv.Position == (core.Position{X: 1, Y: 1}) {
+			if tv.Glyph != world.GlyphZone {
+				t.Fatalf("zone tile glyph = %q, want %q", tv.Glyph, world.GlyphZone)
+			}
+			return
+		}
+	}
+	t.Fatal("zone tile not visible")
+}
@@ -0,0 +1,120 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+func TestExtraction_EntityOnOpenExitLeavesRun(t *testing.T) {
+	a := agent.NewScripted("A") // moves east every tick
+	b := &simpleAgent{id: "B", act: agent.WAIT}
+	rt := New([]agent.Agent{a, b})
+	rt.world.SetPosition("B", world.Position{X: 5, Y: 5})
+	rt.AddWinCondition(Extraction{})
+	rt.AddWinCondition(TickLimit{Ticks: 10})
+
+	// Exit directly east of A, held open for the whole test.
+	exit := world.Position{X: 1, Y: 0}
+	rt.world.AddExit(exit)
+	rt.world.SetExit(0, world.Exit{Position: exit, Open: true, NextToggle: 1000})
+
+	rt.TickOnce()
+	if _, ok := rt.world.PositionOf("A"); ok {
+		t.Fatal("extracted entity still occupies a world position")
+	}
+	if ids := rt.ActiveIDs(); len(ids) != 1 || ids[0] != "B" {
+		t.Fatalf("active entities = %v, want [B]", ids)
+	}
+	if _, ok := rt.SnapshotForDebug("A"); ok {
+		t.Fatal("extracted entity should not receive snapshots")
+	}
+
+	for rt.State() != RunEnded {
+		rt.TickOnce()
+	}
+	res, _ := rt.Result()
+	if res.Condition != "tick-limit" {
+		t.Fatalf("run ended by %q, want tick-limit", res.Condition)
+	}
+	ra, rb := res.Entities[0], res.Entities[1]
+	if ra.Outcome != OutcomeExtracted || !ra.Success || ra.Ticks != 1 || ra.Position.X != exit.X || ra.Position.Y != exit.Y {
+		t.Fatalf("unexpected result for extracted entity: %+v", ra)
+	}
+	if rb.Outcome != OutcomeSurvived || rb.Success {
+		t.Fatalf("unexpected result for remaining entity: %+v", rb)
+	}
+}
+
+func TestExtraction_EndsWhenEveryoneLeaves(t *testing.T) {
+	a := agent.NewScripted("A")
+	rt := New([]agent.Agent{a})
+	rt.AddWinCondition(Extraction{})
+	exit := world.Position{X: 1, Y: 0}
+	rt.world.AddExit(exit)
+	rt.world.SetExit(0, world.Exit{Position: exit, Open: true, NextToggle: 1000})
+
+	rt.TickOnce()
+	if rt.State() != RunEnded {
+		t.Fatalf("state = %v, want ended", rt.State())
+	}
+	res, _ := rt.Result()
+	if res.Condition != "extraction" || len(res.Entities) != 1 || !res.Entities[0].Success {
+		t.Fatalf("unexpected extraction result: %+v", res)
+	}
+}
+
+func TestPlaceExits_DistinctAndClosed(t *testing.T) {
+	rt := New([]agent.Agent{agent.NewScripted("A")})
+	rt.PlaceExits(3)
+	exits := rt.Exits()
+	if len(exits) != 3 {
+		t.Fatalf("placed %d exits, want 3", len(exits))
+	}
+	seen := map[world.Position]bool{}
+	for _, e := range exits {
+		if e.Open {
+			t.Fatalf("exit %+v placed open", e)
+		}
+		if seen[e.Position] {
+			t.Fatalf("duplicate exit at %+v", e.Position)
+		}
+		seen[e.Position] = true
+	}
+}
+
+func TestExtraction_NotPreemptedBySurvival(t *testing.T) {
+	a := agent.NewScripted("A")
+	b := &simpleAgent{id: "B", act: agent.WAIT}
+	agents := []agent.Agent{a, b}
+	rt := New(agents)
+	rt.world.SetPosition("B", world.Position{X: 5, Y: 5})
+	// The order both binaries register them in.
+	rt.AddWinCondition(Survival{})
+	rt.AddWinCondition(Extraction{})
+	exit := world.Position{X: 1, Y: 0}
+	rt.world.AddExit(exit)
+	rt.world.SetExit(0, world.Exit{Position: exit, Open: true, NextToggle: 1000})
+
+	rt.TickOnce()
+	if ids := rt.ActiveIDs(); len(ids) != 1 || ids[0] != "B" {
+		t.Fatalf("active entities = %v, want [B]", ids)
+	}
+	if agents[0] != a || agents[1] != b {
+		t.Fatal("removing an entity rewrote the caller's agent slice")
+	}
+	if rt.State() == RunEnded {
+		res, _ := rt.Result()
+		t.Fatalf("run ended by %q (%s) after an extraction", res.Condition, res.Reason)
+	}
+
+	rt.world.SetPosition("B", world.Position{X: 3, Y: 3})
+	rt.world.AddExit(world.Position{X: 3, Y: 3})
+	rt.world.SetExit(1, world.Exit{Position: world.Position{X: 3, Y: 3}, Open: true, NextToggle: 1000})
+	rt.TickOnce()
+	res, ok := rt.Result()
+	if !ok || res.Condition != "extraction" || !res.Entities[0].Success || !res.Entities[1].Success {
+		t.Fatalf("result = %+v, want both extracted by the extraction condition", res)
+	}
+}
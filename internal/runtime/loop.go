@@ -0,0 +1,104 @@
+package runtime
+
+import (
+	"sync"
+	"time"
+)
+
+// Loop drives a Runtime on a single goroutine. Once Run has started, the
+// runtime and every agent in it belong to that goroutine: other goroutines
+// (persistence, admin, spectators) must reach them through Do, which runs a
+// function between ticks. Anything copied out inside Do is a consistent
+// snapshot of one tick boundary.
+type Loop struct {
+	rt   *Runtime
+	cmds chan loopCmd
+	stop chan struct{}
+	done chan struct{}
+
+	once sync.Once
+	// mu serializes Do calls after the loop goroutine has exited.
+	mu sync.Mutex
+
+	// paused is only touched on the loop goroutine (or under mu once it
+	// has exited).
+	paused bool
+}
+
+type loopCmd struct {
+	fn  func(*Runtime)
+	ack chan struct{}
+}
+
+// NewLoop returns a loop for rt. Call Run to start it.
+func NewLoop(rt *Runtime) *Loop {
+	return &Loop{
+		rt:   rt,
+		cmds: make(chan loopCmd),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// Run ticks the runtime at its configured rate until the run ends, and
+// serves Do calls until Stop is called. It blocks; run it on its own
+// goroutine.
+func (l *Loop) Run() {
+	defer close(l.done)
+	ticker := time.NewTicker(l.rt.TickRate())
+	defer ticker.Stop()
+	ticks := ticker.C
+	for {
+		select {
+		case <-l.stop:
+			return
+		case c := <-l.cmds:
+			c.fn(l.rt)
+			close(c.ack)
+		case <-ticks:
+			if l.paused {
+				continue
+			}
+			l.rt.TickOnce()
+			if l.rt.State() == RunEnded {
+				// Keep serving commands; stop ticking.
+				ticks = nil
+			}
+		}
+	}
+}
+
+// Do runs fn on the loop goroutine between ticks and waits for it to
+// return. After the loop has stopped, fn runs on the caller's goroutine,
+// still one call at a time.
+func (l *Loop) Do(fn func(*Runtime)) {
+	c := loopCmd{fn: fn, ack: make(chan struct{})}
+	select {
+	case l.cmds <- c:
+		<-c.ack
+	case <-l.done:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		fn(l.rt)
+	}
+}
+
+// SetPaused stops or restarts the clock. While paused the loop keeps serving
+// Do calls but the run does not advance.
+func (l *Loop) SetPaused(paused bool) {
+	l.Do(func(*Runtime) { l.paused = paused })
+}
+
+// Paused reports whether the clock is paused.
+func (l *Loop) Paused() bool {
+	var paused bool
+	l.Do(func(*Runtime) { paused = l.paused })
+	return paused
+}
+
+// Stop ends Run and waits for it to return. It must only be called once Run
+// has been started, and is safe to call more than once.
+func (l *Loop) Stop() {
+	l.once.Do(func() { close(l.stop) })
+	<-l.done
+}
@@ -0,0 +1,124 @@
+package runtime
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+)
+
+// TestLoop_ConcurrentAccessIsSerialized mirrors the server: a remote agent
+// fed input from one goroutine, a persistence goroutine copying agent state,
+// and the loop ticking. Run with -race.
+func TestLoop_ConcurrentAccessIsSerialized(t *testing.T) {
+	rh := agent.NewRemoteHumanFromExisting("R", agent.NewMemory(), agent.MaxEnergy)
+	cfg := DefaultConfig()
+	cfg.TickRate = time.Millisecond
+	cfg.InputTimeout = time.Millisecond
+	rt, err := NewWithConfig([]agent.Agent{rh, agent.NewOscillating("O")}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt.AddWinCondition(TickLimit{Ticks: 30})
+
+	loop := NewLoop(rt)
+	go loop.Run()
+	defer loop.Stop()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { // client connection
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-rh.SendObservation:
+			case rh.RecvInput <- "d":
+			}
+		}
+	}()
+	go func() { // persistence
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			loop.Do(func(rt *Runtime) {
+				if rh.Dirty() {
+					_ = rh.State()
+					rh.MarkClean()
+				}
+				_, _ = rt.PositionOf("R")
+			})
+		}
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var state RunState
+		loop.Do(func(rt *Runtime) { state = rt.State() })
+		if state == RunEnded {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("run did not end")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(stop)
+	wg.Wait()
+
+	var tick int
+	loop.Do(func(rt *Runtime) { tick = rt.Tick() })
+	if tick != 30 {
+		t.Fatalf("run ended at tick %d, want 30", tick)
+	}
+}
+
+func TestLoop_DoAfterStopRunsInline(t *testing.T) {
+	rt := New([]agent.Agent{agent.NewScripted("A")})
+	loop := NewLoop(rt)
+	go loop.Run()
+	loop.Stop()
+	loop.Stop()
+	called := false
+	loop.Do(func(*Runtime) { called = true })
+	if !called {
+		t.Fatal("Do after Stop did not run")
+	}
+}
+
+func TestLoop_PauseHoldsTheClock(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TickRate = time.Millisecond
+	rt, err := NewWithConfig([]agent.Agent{agent.NewOscillating("O")}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loop := NewLoop(rt)
+	go loop.Run()
+	defer loop.Stop()
+
+	loop.SetPaused(true)
+	var paused, tick int
+	loop.Do(func(rt *Runtime) { paused = rt.Tick() })
+	time.Sleep(20 * time.Millisecond)
+	loop.Do(func(rt *Runtime) { tick = rt.Tick() })
+	if tick != paused || !loop.Paused() {
+		t.Fatalf("paused loop advanced from tick %d to %d", paused, tick)
+	}
+	loop.SetPaused(false)
+	deadline := time.Now().Add(5 * time.Second)
+	for tick == paused {
+		if time.Now().After(deadline) {
+			t.Fatal("resumed loop did not tick")
+		}
+		time.Sleep(time.Millisecond)
+		loop.Do(func(rt *Runtime) { tick = rt.Tick() })
+	}
+}
@@ -0,0 +1,237 @@
+package runtime
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+)
+
+// Counter is a monotonically increasing metric.
+type Counter struct{ v atomic.Uint64 }
+
+// Add increases the counter by n.
+func (c *Counter) Add(n int) {
+	if n > 0 {
+		c.v.Add(uint64(n))
+	}
+}
+
+// Inc increases the counter by one.
+func (c *Counter) Inc() { c.v.Add(1) }
+
+// Value returns the current count.
+func (c *Counter) Value() uint64 { return c.v.Load() }
+
+// Gauge is a metric that goes up and down.
+type Gauge struct{ v atomic.Int64 }
+
+// Set replaces the gauge value.
+func (g *Gauge) Set(n int) { g.v.Store(int64(n)) }
+
+// Add moves the gauge by n, which may be negative.
+func (g *Gauge) Add(n int) { g.v.Add(int64(n)) }
+
+// Value returns the current value.
+func (g *Gauge) Value() int64 { return g.v.Load() }
+
+// Histogram counts observations into fixed upper-bound buckets.
+type Histogram struct {
+	mu     sync.Mutex
+	bounds []float64
+	counts []uint64 // counts[i] observations <= bounds[i]; last is +Inf
+	sum    float64
+	n      uint64
+}
+
+// Observe records v.
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	i := 0
+	for i < len(h.bounds) && v > h.bounds[i] {
+		i++
+	}
+	h.counts[i]++
+	h.sum += v
+	h.n++
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.n
+}
+
+type metricKind string
+
+const (
+	kindCounter   metricKind = "counter"
+	kindGauge     metricKind = "gauge"
+	kindHistogram metricKind = "histogram"
+)
+
+type metricEntry struct {
+	name, help string
+	kind       metricKind
+	counter    *Counter
+	gauge      *Gauge
+	histogram  *Histogram
+}
+
+// Metrics is a registry of named metrics. Metrics are updated from the
+// loop goroutine and read from anywhere, so every metric is safe for
+// concurrent use. The runtime registers its own metrics in NewMetrics;
+// callers may register more (the server counts connections) and expose the
+// lot with WritePrometheus.
+type Metrics struct {
+	// Ticks counts ticks run.
+	Ticks *Counter
+	// TickDuration observes how long each tick took, in seconds, including
+	// waiting for input.
+	TickDuration *Histogram
+	// InputTimeouts counts remote agents that sent no input within
+	// InputTimeout.
+	InputTimeouts *Counter
+	// Decisions counts agent decisions; divide Scars by it for a scar rate.
+	Decisions *Counter
+	// ContagionTransfers counts beliefs adopted from other agents.
+	ContagionTransfers *Counter
+	// Scars counts memory tiles scarred by conflicting observations.
+	Scars *Counter
+	// Hallucinations counts hallucinated tiles and entities shown to agents.
+	Hallucinations *Counter
+	// Forgotten counts beliefs lost to decay or memory capacity.
+	Forgotten *Counter
+	// ActiveEntities is the number of entities still in the run.
+	ActiveEntities *Gauge
+
+	mu      sync.Mutex
+	entries []metricEntry
+}
+
+// tickBuckets are the TickDuration bucket bounds in seconds.
+var tickBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// NewMetrics returns a registry holding the runtime metrics.
+func NewMetrics() *Metrics {
+	m := &Metrics{}
+	m.Ticks = m.NewCounter("nightshade_ticks_total", "Ticks run.")
+	m.TickDuration = m.NewHistogram("nightshade_tick_duration_seconds", "Time taken by each tick, including waiting for input.", tickBuckets)
+	m.InputTimeouts = m.NewCounter("nightshade_input_timeouts_total", "Remote agents that sent no input before the input timeout.")
+	m.Decisions = m.NewCounter("nightshade_decisions_total", "Agent decisions made.")
+	m.ContagionTransfers = m.NewCounter("nightshade_contagion_transfers_total", "Beliefs adopted from nearby agents.")
+	m.Scars = m.NewCounter("nightshade_scars_total", "Memory tiles scarred by conflicting observations.")
+	m.Hallucinations = m.NewCounter("nightshade_hallucinations_total", "Hallucinated tiles and entities shown to agents.")
+	m.Forgotten = m.NewCounter("nightshade_forgotten_beliefs_total", "Beliefs agents lost to decay or memory capacity.")
+	m.ActiveEntities = m.NewGauge("nightshade_active_entities", "Entities still taking part in the run.")
+	return m
+}
+
+func (m *Metrics) register(e metricEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, e)
+}
+
+// NewCounter registers and returns a counter.
+func (m *Metrics) NewCounter(name, help string) *Counter {
+	c := &Counter{}
+	m.register(metricEntry{name: name, help: help, kind: kindCounter, counter: c})
+	return c
+}
+
+// NewGauge registers and returns a gauge.
+func (m *Metrics) NewGauge(name, help string) *Gauge {
+	g := &Gauge{}
+	m.register(metricEntry{name: name, help: help, kind: kindGauge, gauge: g})
+	return g
+}
+
+// NewHistogram registers and returns a histogram with the given ascending
+// bucket bounds.
+func (m *Metrics) NewHistogram(name, help string, bounds []float64) *Histogram {
+	h := &Histogram{bounds: append([]float64(nil), bounds...), counts: make([]uint64, len(bounds)+1)}
+	m.register(metricEntry{name: name, help: help, kind: kindHistogram, histogram: h})
+	return h
+}
+
+// WritePrometheus writes every metric in the Prometheus text exposition
+// format, in registration order.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	entries := append([]metricEntry(nil), m.entries...)
+	m.mu.Unlock()
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", e.name, e.help, e.name, e.kind); err != nil {
+			return err
+		}
+		var err error
+		switch e.kind {
+		case kindCounter:
+			_, err = fmt.Fprintf(w, "%s %d\n", e.name, e.counter.Value())
+		case kindGauge:
+			_, err = fmt.Fprintf(w, "%s %d\n", e.name, e.gauge.Value())
+		case kindHistogram:
+			err = e.histogram.write(w, e.name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *Histogram) write(w io.Writer, name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var cum uint64
+	for i, c := range h.counts {
+		cum += c
+		le := "+Inf"
+		if i < len(h.bounds) {
+			le = formatFloat(h.bounds[i])
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket{le=%q} %d\n", name, le, cum); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%s_sum %s\n%s_count %d\n", name, formatFloat(h.sum), name, h.n)
+	return err
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Metrics returns the runtime's metrics registry.
+func (r *Runtime) Metrics() *Metrics {
+	return r.metrics
+}
+
+// recordTick updates the per-tick metrics once a tick has resolved.
+func (r *Runtime) recordTick(started time.Time, decisions Decisions) {
+	m := r.metrics
+	m.Ticks.Inc()
+	m.TickDuration.Observe(time.Since(started).Seconds())
+	m.Decisions.Add(len(decisions))
+	m.ActiveEntities.Set(len(r.agents))
+}
+
+// recordDecision adds a's last decision to the cognition metrics.
+func (r *Runtime) recordDecision(a agent.Agent) {
+	s, ok := a.(interface{ LastDecision() agent.DecisionStats })
+	if !ok {
+		return
+	}
+	st := s.LastDecision()
+	r.metrics.ContagionTransfers.Add(st.Transfers)
+	r.metrics.Scars.Add(st.Scars)
+	r.metrics.Hallucinations.Add(st.Hallucinations)
+	r.metrics.Forgotten.Add(st.Forgotten)
+}
@@ -0,0 +1,106 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+)
+
+// troubled is a waiting agent whose every decision reports the same stats.
+type troubled struct {
+	simpleAgent
+	stats agent.DecisionStats
+}
+
+func (t *troubled) LastDecision() agent.DecisionStats { return t.stats }
+
+func TestMetrics_CountTicksAndCognition(t *testing.T) {
+	rh := agent.NewRemoteHumanFromExisting("R", agent.NewMemory(), agent.MaxEnergy)
+	cfg := DefaultConfig()
+	cfg.InputTimeout = time.Millisecond
+	rt, err := NewWithConfig([]agent.Agent{
+		rh,
+		&troubled{simpleAgent{id: "T", act: agent.WAIT}, agent.DecisionStats{Transfers: 2, Scars: 1, Hallucinations: 3}},
+	}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		rt.TickOnce()
+	}
+	m := rt.Metrics()
+	checks := []struct {
+		name      string
+		got, want uint64
+	}{
+		{"ticks", m.Ticks.Value(), 3},
+		{"input timeouts", m.InputTimeouts.Value(), 3},
+		{"decisions", m.Decisions.Value(), 6},
+		{"transfers", m.ContagionTransfers.Value(), 6},
+		{"scars", m.Scars.Value(), 3},
+		{"hallucinations", m.Hallucinations.Value(), 9},
+		{"tick durations", m.TickDuration.Count(), 3},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+	if m.ActiveEntities.Value() != 2 {
+		t.Errorf("active entities = %d", m.ActiveEntities.Value())
+	}
+}
+
+func TestMetrics_SharedRegistryFromConfig(t *testing.T) {
+	m := NewMetrics()
+	conns := m.NewGauge("test_connections", "Open connections.")
+	cfg := DefaultConfig()
+	cfg.Metrics = m
+	rt, err := NewWithConfig([]agent.Agent{&simpleAgent{id: "A", act: agent.WAIT}}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt.Metrics() != m {
+		t.Fatal("runtime did not use the configured registry")
+	}
+	rt.TickOnce()
+	conns.Add(2)
+	conns.Add(-1)
+
+	var b strings.Builder
+	if err := m.WritePrometheus(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"# TYPE nightshade_ticks_total counter\nnightshade_ticks_total 1\n",
+		"# TYPE nightshade_tick_duration_seconds histogram\n",
+		"nightshade_tick_duration_seconds_bucket{le=\"+Inf\"} 1\n",
+		"nightshade_tick_duration_seconds_count 1\n",
+		"# HELP test_connections Open connections.\n# TYPE test_connections gauge\ntest_connections 1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("exposition missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestHistogram_BucketsAreCumulative(t *testing.T) {
+	m := &Metrics{}
+	h := m.NewHistogram("h", "Test.", []float64{1, 2})
+	for _, v := range []float64{0.5, 1, 1.5, 3} {
+		h.Observe(v)
+	}
+	var b strings.Builder
+	if err := m.WritePrometheus(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "# HELP h Test.\n# TYPE h histogram\n" +
+		"h_bucket{le=\"1\"} 2\nh_bucket{le=\"2\"} 3\nh_bucket{le=\"+Inf\"} 4\n" +
+		"h_sum 6\nh_count 4\n"
+	if b.String() != want {
+		t.Fatalf("exposition = %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,298 @@
+package runtime
+
+import (
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+)
+
+// RunState is the lifecycle phase of a run. A run moves strictly forward:
+// lobby -> running -> ended.
+type RunState int
+
+const (
+	RunLobby RunState = iota
+	RunRunning
+	RunEnded
+)
+
+func (s RunState) String() string {
+	switch s {
+	case RunLobby:
+		return "lobby"
+	case RunRunning:
+		return "running"
+	case RunEnded:
+		return "ended"
+	default:
+		return "unknown"
+	}
+}
+
+// Outcome records how an entity left the run.
+type Outcome string
+
+const (
+	// OutcomeSurvived is assigned to entities still active when the run ends.
+	OutcomeSurvived Outcome = "survived"
+	// OutcomeExtracted is assigned to entities that reached an open exit.
+	// Extraction is a success in its own right, whatever ends the run.
+	OutcomeExtracted Outcome = "extracted"
+	// OutcomeKicked is assigned to entities an operator removed from the run.
+	OutcomeKicked Outcome = "kicked"
+)
+
+// Verdict is the result of evaluating a WinCondition for one tick. Winners
+// lists the entity IDs that met the condition; it may be empty when a run
+// ends without anyone satisfying it.
+type Verdict struct {
+	Ended   bool
+	Reason  string
+	Winners []string
+}
+
+// WinCondition is a pluggable success criterion. Conditions are evaluated
+// in registration order after resolution on every tick; the first verdict
+// with Ended set ends the run. Conditions must be deterministic and must not
+// mutate the runtime.
+type WinCondition interface {
+	Name() string
+	Evaluate(r *Runtime) Verdict
+}
+
+// EntityResult is the final record for a single entity. Entities are known
+// by position, behavior and survival time, not by name.
+type EntityResult struct {
+	ID       string        `json:"id"`
+	Outcome  Outcome       `json:"outcome"`
+	Success  bool          `json:"success"`
+	Ticks    int           `json:"ticks"`
+	Position core.Position `json:"position"`
+	Energy   int           `json:"energy"`
+}
+
+// RunResult is produced exactly once when a run ends.
+type RunResult struct {
+	StartTick int            `json:"startTick"`
+	EndTick   int            `json:"endTick"`
+	Condition string         `json:"condition"`
+	Reason    string         `json:"reason"`
+	Entities  []EntityResult `json:"entities"`
+}
+
+// TickLimit ends the run after a fixed number of ticks. It names no winners.
+type TickLimit struct {
+	Ticks int
+}
+
+func (c TickLimit) Name() string { return "tick-limit" }
+
+func (c TickLimit) Evaluate(r *Runtime) Verdict {
+	if c.Ticks > 0 && r.Tick()-r.startTick >= c.Ticks {
+		return Verdict{Ended: true, Reason: "tick limit reached"}
+	}
+	return Verdict{}
+}
+
+// Survival ends the run when at most one entity has not been eliminated,
+// provided the run started with more than one. Extraction is not
+// elimination: an entity that got out is still standing, so Survival leaves
+// runs its rivals walk out of to Extraction. The remaining entity, if any,
+// wins.
+type Survival struct{}
+
+func (Survival) Name() string { return "survival" }
+
+func (Survival) Evaluate(r *Runtime) Verdict {
+	if len(r.roster) < 2 {
+		return Verdict{}
+	}
+	standing := []string{}
+	for _, a := range r.roster {
+		if res, ok := r.departed[a.ID()]; !ok || res.Outcome == OutcomeExtracted {
+			standing = append(standing, a.ID())
+		}
+	}
+	if len(standing) > 1 {
+		return Verdict{}
+	}
+	return Verdict{Ended: true, Reason: "last entity remaining", Winners: standing}
+}
+
+// Extraction ends the run once no entity remains active. Every entity that
+// reached an open exit wins.
+type Extraction struct{}
+
+func (Extraction) Name() string { return "extraction" }
+
+func (Extraction) Evaluate(r *Runtime) Verdict {
+	if len(r.agents) > 0 {
+		return Verdict{}
+	}
+	winners := []string{}
+	for _, a := range r.roster {
+		if res, ok := r.departed[a.ID()]; ok && res.Outcome == OutcomeExtracted {
+			winners = append(winners, a.ID())
+		}
+	}
+	return Verdict{Ended: true, Reason: "no entities remain", Winners: winners}
+}
+
+// Control ends the run once any entity has held a single zone for Ticks
+// cumulative ticks. Every entity reaching the threshold on the same tick
+// wins.
+type Control struct {
+	Ticks int
+}
+
+func (Control) Name() string { return "control" }
+
+func (c Control) Evaluate(r *Runtime) Verdict {
+	if c.Ticks <= 0 {
+		return Verdict{}
+	}
+	winners := []string{}
+	for _, a := range r.roster {
+		for _, scores := range r.influence {
+			if scores[a.ID()] >= c.Ticks {
+				winners = append(winners, a.ID())
+				break
+			}
+		}
+	}
+	if len(winners) == 0 {
+		return Verdict{}
+	}
+	return Verdict{Ended: true, Reason: "zone held", Winners: winners}
+}
+
+// State returns the current lifecycle phase of the run.
+func (r *Runtime) State() RunState {
+	return r.state
+}
+
+// Start moves the run from the lobby into the running phase. Calling Start
+// on a running or ended run has no effect.
+func (r *Runtime) Start() {
+	if r.state != RunLobby {
+		return
+	}
+	r.state = RunRunning
+	r.startTick = r.tick
+	r.log().Info("run started", "entities", len(r.agents), "seed", r.cfg.Seed)
+}
+
+// AddWinCondition registers a success criterion for the run.
+func (r *Runtime) AddWinCondition(c WinCondition) {
+	r.conditions = append(r.conditions, c)
+}
+
+// OnEnd registers a callback invoked once with the final RunResult. It runs
+// on the tick goroutine; callers that persist the result should copy it
+// rather than block.
+func (r *Runtime) OnEnd(fn func(RunResult)) {
+	r.onEnd = fn
+}
+
+// End forcibly ends the run with the given reason and no winners.
+func (r *Runtime) End(reason string) {
+	if r.state == RunEnded {
+		return
+	}
+	r.finish("", Verdict{Ended: true, Reason: reason})
+}
+
+// Result returns the final RunResult once the run has ended.
+func (r *Runtime) Result() (RunResult, bool) {
+	if r.result == nil {
+		return RunResult{}, false
+	}
+	return *r.result, true
+}
+
+// ActiveIDs returns the IDs of entities still taking part in the run, in
+// registration order.
+func (r *Runtime) ActiveIDs() []string {
+	ids := make([]string, 0, len(r.agents))
+	for _, a := range r.agents {
+		ids = append(ids, a.ID())
+	}
+	return ids
+}
+
+// evaluateWinConditions checks registered conditions in order and ends the
+// run on the first verdict that reports Ended.
+func (r *Runtime) evaluateWinConditions() {
+	if r.state != RunRunning {
+		return
+	}
+	for _, c := range r.conditions {
+		v := c.Evaluate(r)
+		if v.Ended {
+			r.finish(c.Name(), v)
+			return
+		}
+	}
+}
+
+// remove takes an entity out of the active set, recording its outcome. The
+// entity no longer observes, decides or occupies a world position.
+func (r *Runtime) remove(id string, outcome Outcome) {
+	for i, a := range r.agents {
+		if a.ID() != id {
+			continue
+		}
+		res := r.entityResult(a, outcome)
+		res.Success = outcome == OutcomeExtracted
+		r.departed[id] = res
+		r.agents = append(r.agents[:i], r.agents[i+1:]...)
+		r.world.Remove(id)
+		r.log().Info("entity left the run", "agent", id, "outcome", outcome, "x", res.Position.X, "y", res.Position.Y)
+		return
+	}
+}
+
+func (r *Runtime) entityResult(a agent.Agent, outcome Outcome) EntityResult {
+	res := EntityResult{
+		ID:      a.ID(),
+		Outcome: outcome,
+		Ticks:   r.tick - r.startTick,
+	}
+	if pos, ok := r.world.PositionOf(a.ID()); ok {
+		res.Position = core.Position{X: pos.X, Y: pos.Y}
+	}
+	if e, ok := a.(interface{ Energy() int }); ok {
+		res.Energy = e.Energy()
+	}
+	return res
+}
+
+// finish freezes the run and builds the per-entity result record in
+// registration order.
+func (r *Runtime) finish(condition string, v Verdict) {
+	winners := make(map[string]struct{}, len(v.Winners))
+	for _, id := range v.Winners {
+		winners[id] = struct{}{}
+	}
+	res := RunResult{
+		StartTick: r.startTick,
+		EndTick:   r.tick,
+		Condition: condition,
+		Reason:    v.Reason,
+	}
+	for _, a := range r.roster {
+		er, ok := r.departed[a.ID()]
+		if !ok {
+			er = r.entityResult(a, OutcomeSurvived)
+		}
+		if _, won := winners[a.ID()]; won {
+			er.Success = true
+		}
+		res.Entities = append(res.Entities, er)
+	}
+	r.state = RunEnded
+	r.result = &res
+	r.log().Info("run ended", "condition", condition, "reason", v.Reason, "winners", v.Winners)
+	if r.onEnd != nil {
+		r.onEnd(res)
+	}
+}
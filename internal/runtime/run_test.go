@@ -0,0 +1,121 @@
+package runtime
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+)
+
+func TestRunLifecycle_LobbyRunningEnded(t *testing.T) {
+	rt := New([]agent.Agent{agent.NewScripted("A"), agent.NewOscillating("B")})
+	if rt.State() != RunLobby {
+		t.Fatalf("new runtime state = %v, want lobby", rt.State())
+	}
+	rt.AddWinCondition(TickLimit{Ticks: 3})
+
+	// First tick implicitly starts the run.
+	rt.TickOnce()
+	if rt.State() != RunRunning {
+		t.Fatalf("state after first tick = %v, want running", rt.State())
+	}
+	rt.TickOnce()
+	rt.TickOnce()
+	if rt.State() != RunEnded {
+		t.Fatalf("state after limit = %v, want ended", rt.State())
+	}
+
+	// Ticking an ended run is a no-op.
+	if d := rt.TickOnce(); len(d) != 0 {
+		t.Fatalf("ended run produced decisions: %v", d)
+	}
+	if rt.Tick() != 3 {
+		t.Fatalf("ended run advanced tick to %d", rt.Tick())
+	}
+}
+
+func TestRunResult_RecordsEveryEntity(t *testing.T) {
+	var got []RunResult
+	rt := New([]agent.Agent{agent.NewScripted("A"), agent.NewOscillating("B")})
+	rt.AddWinCondition(TickLimit{Ticks: 2})
+	rt.OnEnd(func(res RunResult) { got = append(got, res) })
+
+	for rt.State() != RunEnded {
+		rt.TickOnce()
+	}
+	if len(got) != 1 {
+		t.Fatalf("OnEnd called %d times, want 1", len(got))
+	}
+	res, ok := rt.Result()
+	if !ok {
+		t.Fatal("missing run result")
+	}
+	if res.Condition != "tick-limit" || res.StartTick != 0 || res.EndTick != 2 {
+		t.Fatalf("unexpected result header: %+v", res)
+	}
+	if len(res.Entities) != 2 || res.Entities[0].ID != "A" || res.Entities[1].ID != "B" {
+		t.Fatalf("unexpected entity results: %+v", res.Entities)
+	}
+	a := res.Entities[0]
+	if a.Outcome != OutcomeSurvived || a.Success || a.Ticks != 2 {
+		t.Fatalf("unexpected result for A: %+v", a)
+	}
+	if a.Position.X != 2 || a.Energy != agent.MaxEnergy-2*agent.MoveEnergyCost {
+		t.Fatalf("unexpected final state for A: %+v", a)
+	}
+}
+
+func TestRunEnd_ForcedEndHasNoWinners(t *testing.T) {
+	rt := New([]agent.Agent{agent.NewScripted("A")})
+	rt.Start()
+	rt.End("operator stop")
+	res, ok := rt.Result()
+	if !ok || res.Reason != "operator stop" {
+		t.Fatalf("unexpected forced result: %+v ok=%v", res, ok)
+	}
+	for _, e := range res.Entities {
+		if e.Success {
+			t.Fatalf("forced end should not name winners: %+v", e)
+		}
+	}
+}
+
+func TestSurvival_SingleEntityRunDoesNotEnd(t *testing.T) {
+	rt := New([]agent.Agent{agent.NewScripted("A")})
+	rt.AddWinCondition(Survival{})
+	rt.TickOnce()
+	if rt.State() != RunRunning {
+		t.Fatalf("single-entity survival run ended early: %v", rt.State())
+	}
+}
+
+func TestRun_LogsCarryTickAndAgent(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cfg.InputTimeout = time.Millisecond
+	rh := agent.NewRemoteHumanFromExisting("R", agent.NewMemory(), agent.MaxEnergy)
+	rt, err := NewWithConfig([]agent.Agent{rh, &simpleAgent{id: "B", act: agent.WAIT}}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt.AddWinCondition(Survival{})
+	rt.TickOnce()
+	rt.Kick("B")
+	rt.TickOnce()
+
+	out := buf.String()
+	for _, want := range []string{
+		`msg="run started" tick=0 entities=2`,
+		`msg="no input before timeout" tick=0 agent=R`,
+		`msg="entity left the run" tick=1 agent=B outcome=kicked`,
+		`msg="run ended" tick=2 condition=survival reason="last entity remaining" winners=[R]`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log missing %q:\n%s", want, out)
+		}
+	}
+}
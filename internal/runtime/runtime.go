@@ -1,37 +1,136 @@
 package runtime
 
 import (
+	"fmt"
+	"log/slog"
+	"time"
+
 	"github.com/divijg19/Nightshade/internal/agent"
 	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/util"
 	"github.com/divijg19/Nightshade/internal/world"
 )
 
 const defaultVisibilityRadius = 2
 
+// defaultSeed seeds the runtime RNG when no seed is supplied.
+const defaultSeed = 1
+
 type Runtime struct {
 	tick   int
 	agents []agent.Agent
 	world  *world.World
+	rng    *util.Rand
+	cfg    Config
+
+	// lastActions holds each entity's resolved action from the previous
+	// tick; perception modifiers depend on it.
+	lastActions map[string]agent.Action
+
+	// run lifecycle
+	state      RunState
+	startTick  int
+	roster     []agent.Agent
+	departed   map[string]EntityResult
+	conditions []WinCondition
+	result     *RunResult
+	onEnd      func(RunResult)
+	onTick     func(*Runtime)
+
+	// control zones
+	holders   map[string]string
+	influence map[string]map[string]int
+
+	metrics *Metrics
+	logger  *slog.Logger
 }
 
 func New(agents []agent.Agent) *Runtime {
 	// Use the package bounds constants to construct the world so tests
 	// that reference `world.Width`/`world.Height` match runtime size.
-	w := world.New(world.Width, world.Height)
+	return newRuntime(agents, world.New(world.Width, world.Height), DefaultConfig())
+}
+
+// NewWithConfig builds a runtime from cfg. The stage is cfg.World when set,
+// otherwise it is generated from cfg.Layout with an RNG seeded by cfg.Seed,
+// so the same configuration always produces the same run.
+func NewWithConfig(agents []agent.Agent, cfg Config) (*Runtime, error) {
+	w := cfg.World
+	if w == nil {
+		if cfg.Width <= 0 || cfg.Height <= 0 {
+			return nil, fmt.Errorf("invalid world size %dx%d", cfg.Width, cfg.Height)
+		}
+		var err error
+		w, err = world.Generate(cfg.Layout, cfg.Width, cfg.Height, util.NewRand(cfg.Seed), cfg.Gen)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cfg.Width, cfg.Height = w.Width(), w.Height()
+	if cfg.VisibilityRadius < 0 {
+		return nil, fmt.Errorf("invalid visibility radius %d", cfg.VisibilityRadius)
+	}
+	if cfg.TickRate <= 0 {
+		return nil, fmt.Errorf("invalid tick rate %v", cfg.TickRate)
+	}
+	if cfg.InputTimeout <= 0 {
+		return nil, fmt.Errorf("invalid input timeout %v", cfg.InputTimeout)
+	}
+	return newRuntime(agents, w, cfg), nil
+}
 
-	for i, a := range agents {
-		w.SetPosition(a.ID(), world.Position{
-			X: i,
-			Y: 0,
-		})
+func newRuntime(agents []agent.Agent, w *world.World, cfg Config) *Runtime {
+	rng := util.NewRand(cfg.Seed)
+	ids := make([]string, 0, len(agents))
+	for _, a := range agents {
+		ids = append(ids, a.ID())
+		cfg.Profiles.Apply(a)
+	}
+	placeAgents(w, ids, cfg.Spawn, cfg.Positions, rng)
+	metrics := cfg.Metrics
+	if metrics == nil {
+		metrics = NewMetrics()
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
 	}
 	return &Runtime{
-		tick:   0,
-		agents: agents,
-		world:  w,
+		tick:     0,
+		agents:   append([]agent.Agent(nil), agents...),
+		world:    w,
+		rng:      rng,
+		cfg:      cfg,
+		state:    RunLobby,
+		roster:   append([]agent.Agent(nil), agents...),
+		departed: make(map[string]EntityResult),
+
+		lastActions: make(map[string]agent.Action),
+
+		holders:   make(map[string]string),
+		influence: make(map[string]map[string]int),
+
+		metrics: metrics,
+		logger:  logger,
 	}
 }
 
+// log returns the runtime logger annotated with the current tick.
+func (r *Runtime) log() *slog.Logger {
+	return r.logger.With("tick", r.tick)
+}
+
+// Config returns the configuration the runtime was built with.
+func (r *Runtime) Config() Config {
+	return r.cfg
+}
+
+// TickRate returns the configured interval between ticks for callers that
+// drive the clock.
+func (r *Runtime) TickRate() time.Duration {
+	return r.cfg.TickRate
+}
+
 func (r *Runtime) Tick() int {
 	return r.tick
 }
@@ -43,12 +142,19 @@ func (r *Runtime) advanceTick() {
 func (r *Runtime) SnapshotForDebug(agentID string) (Snapshot, bool) {
 	for _, a := range r.agents {
 		if a.ID() == agentID {
-			return r.snapshotFor(a, agent.Action(-1)), true
+			return r.snapshotFor(a, r.previousAction(agentID)), true
 		}
 	}
 	return Snapshot{}, false
 }
 
+// PositionOf returns the current position of entity id, if it is on the
+// stage.
+func (r *Runtime) PositionOf(id string) (core.Position, bool) {
+	p, ok := r.world.PositionOf(id)
+	return core.Position{X: p.X, Y: p.Y}, ok
+}
+
 // MarkerPosition returns the authoritative marker position in world
 // coordinates. This is a debug accessor and does not expose agent memory
 // or change any semantics.
@@ -56,3 +162,27 @@ func (r *Runtime) MarkerPosition() core.Position {
 	mp := r.world.MarkerPosition()
 	return core.Position{X: mp.X, Y: mp.Y}
 }
+
+// PlaceExits places n closed exits at distinct unoccupied floor positions
+// chosen by the seeded RNG. Exits open on their own schedule once the run starts.
+func (r *Runtime) PlaceExits(n int) {
+	w, h := r.world.Width(), r.world.Height()
+	placed := 0
+	for attempts := 0; placed < n && attempts < n*w*h; attempts++ {
+		pos := world.Position{X: r.rng.Intn(w), Y: r.rng.Intn(h)}
+		if !r.world.Passable(pos) || r.world.Occupied(pos) {
+			continue
+		}
+		if _, ok := r.world.ExitAt(pos); ok {
+			continue
+		}
+		r.world.AddExit(pos)
+		placed++
+	}
+}
+
+// Exits returns the authoritative exit state. Like MarkerPosition this is a
+// debug accessor.
+func (r *Runtime) Exits() []world.Exit {
+	return r.world.Exits()
+}
@@ -10,6 +10,21 @@ type Snapshot struct {
 	Energy   int
 	Visible  []core.TileView
 	Known    []core.TileView
+
+	// Entities lists other entities the agent can currently see, ordered by
+	// position (row-major).
+	Entities []core.EntityView
+
+	// Sounds holds noise cues from entities the agent cannot see.
+	Sounds []core.SoundCue
+
+	// VisibilityRadius is the effective radius used to compute Visible,
+	// after perception modifiers.
+	VisibilityRadius int
+
+	// Seed seeds the agent's own random choices this tick. It is derived
+	// from the run seed, so replays draw the same values.
+	Seed uint64
 }
 
 func (s Snapshot) KnownTiles() []core.TileView {
@@ -30,3 +45,16 @@ func (s Snapshot) TickValue() int { return s.Tick }
 // This is a lightweight accessor that exposes the authoritative position but
 // does not expose any memory or age information.
 func (s Snapshot) PositionValue() core.Position { return s.Position }
+
+// EntitiesValue returns the other entities visible this tick.
+func (s Snapshot) EntitiesValue() []core.EntityView { return s.Entities }
+
+// SoundsValue returns the noise cues heard this tick.
+func (s Snapshot) SoundsValue() []core.SoundCue { return s.Sounds }
+
+// VisibilityRadiusValue returns the effective visibility radius so renderers
+// can size their viewport without duplicating runtime rules.
+func (s Snapshot) VisibilityRadiusValue() int { return s.VisibilityRadius }
+
+// SeedValue returns the seed for the agent's random choices this tick.
+func (s Snapshot) SeedValue() uint64 { return s.Seed }
@@ -0,0 +1,247 @@
+package runtime
+
+import (
+	"strings"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/game"
+	"github.com/divijg19/Nightshade/internal/util"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+// GlyphFrameFloor is how empty floor is drawn in a Frame.
+const GlyphFrameFloor = '.'
+
+// FrameEntity is one active entity in a Frame. Hidden reports whether other
+// entities would need to OBSERVE to see it; spectators always do.
+type FrameEntity struct {
+	ID       string        `json:"id"`
+	Position core.Position `json:"position"`
+	Hidden   bool          `json:"hidden"`
+}
+
+// Frame is the authoritative view of the whole stage at a tick boundary,
+// for spectators. Unlike a Snapshot it is not limited by any observer's
+// perception: every active entity is listed, concealed or not.
+type Frame struct {
+	Tick   int    `json:"tick"`
+	State  string `json:"state"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+
+	// Rows holds one string per stage row, drawn with world glyphs and
+	// GlyphFrameFloor for empty floor. Entities are not drawn in.
+	Rows []string `json:"rows"`
+
+	// Entities lists active entities in registration order.
+	Entities []FrameEntity `json:"entities"`
+
+	// Departed lists entities that left the run on this tick.
+	Departed []EntityResult `json:"departed"`
+}
+
+// Frame captures the stage as it stands now.
+func (r *Runtime) Frame() Frame {
+	w, h := r.world.Width(), r.world.Height()
+	f := Frame{
+		Tick:     r.tick,
+		State:    r.state.String(),
+		Width:    w,
+		Height:   h,
+		Rows:     make([]string, 0, h),
+		Entities: []FrameEntity{},
+		Departed: r.departedAt(r.tick),
+	}
+	var b strings.Builder
+	for y := 0; y < h; y++ {
+		b.Reset()
+		for x := 0; x < w; x++ {
+			g := r.world.GlyphAt(world.Position{X: x, Y: y})
+			if g == world.GlyphFloor {
+				g = GlyphFrameFloor
+			}
+			b.WriteRune(g)
+		}
+		f.Rows = append(f.Rows, b.String())
+	}
+	for _, a := range r.agents {
+		pos, ok := r.world.PositionOf(a.ID())
+		if !ok {
+			continue
+		}
+		f.Entities = append(f.Entities, FrameEntity{
+			ID:       a.ID(),
+			Position: core.Position{X: pos.X, Y: pos.Y},
+			Hidden:   game.Concealed(r.previousAction(a.ID()), r.world.Terrain(pos)),
+		})
+	}
+	return f
+}
+
+// departedAt returns the entities that left the run on the given tick, in
+// registration order.
+func (r *Runtime) departedAt(tick int) []EntityResult {
+	out := []EntityResult{}
+	for _, a := range r.roster {
+		if res, ok := r.departed[a.ID()]; ok && r.startTick+res.Ticks == tick {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// OnTick registers a callback invoked at the end of every tick that ran,
+// after win conditions have been evaluated. Like OnEnd it runs on the tick
+// goroutine and must not block.
+func (r *Runtime) OnTick(fn func(*Runtime)) {
+	r.onTick = fn
+}
+
+// Camera is where the director points the broadcast.
+type Camera struct {
+	Subject  string        `json:"subject"`
+	Position core.Position `json:"position"`
+	Reason   string        `json:"reason"`
+}
+
+// interest ranks what makes an entity worth watching. Higher cuts in over
+// lower.
+type interest int
+
+const (
+	interestNone interest = iota
+	interestHallucination
+	interestConflict
+	interestDeparture
+)
+
+func (i interest) String() string {
+	switch i {
+	case interestHallucination:
+		return "hallucination"
+	case interestConflict:
+		return "conflict"
+	case interestDeparture:
+		return "departure"
+	default:
+		return "follow"
+	}
+}
+
+// DefaultDwell is how many ticks the director holds a shot before an event
+// of equal or lower interest may take the camera.
+const DefaultDwell = 5
+
+// Director picks which entity the broadcast camera follows. Each tick it
+// looks for something worth watching: an entity leaving the run, a
+// conflict (an ATTACK, or two entities standing next to each other), or an
+// entity whose last decision was clouded by hallucination. A more
+// interesting event cuts away immediately; otherwise the current shot is
+// held for Dwell ticks so the camera does not flicker. With nothing
+// happening it keeps following the current subject, or the first active
+// entity. The choice depends only on runtime state, so a replayed run is
+// directed the same way.
+type Director struct {
+	Dwell int
+
+	cam   Camera
+	level interest
+	since int
+}
+
+// NewDirector returns a director holding shots for DefaultDwell ticks.
+func NewDirector() *Director {
+	return &Director{Dwell: DefaultDwell}
+}
+
+type shot struct {
+	subject string
+	pos     core.Position
+	level   interest
+}
+
+// Update chooses the camera for the runtime's current tick.
+func (d *Director) Update(r *Runtime) Camera {
+	best := d.best(r)
+	held := d.cam.Subject != "" && r.Tick()-d.since < d.Dwell
+	pos, present := d.locate(r, d.cam.Subject, held)
+	switch {
+	case best.level > interestNone && (!present || !held || best.level > d.level):
+		d.cut(r, best)
+	case present:
+		d.cam.Position = pos
+		if !held {
+			d.level = interestNone
+			d.cam.Reason = interestNone.String()
+		}
+	default:
+		if ids := r.ActiveIDs(); len(ids) > 0 {
+			p, _ := r.PositionOf(ids[0])
+			d.cut(r, shot{subject: ids[0], pos: p})
+		}
+	}
+	return d.cam
+}
+
+func (d *Director) cut(r *Runtime, s shot) {
+	d.cam = Camera{Subject: s.subject, Position: s.pos, Reason: s.level.String()}
+	d.level = s.level
+	d.since = r.Tick()
+}
+
+// locate returns where subject is. An entity that has left the run is
+// still located at its last position while its shot is held.
+func (d *Director) locate(r *Runtime, subject string, held bool) (core.Position, bool) {
+	if subject == "" {
+		return core.Position{}, false
+	}
+	if p, ok := r.PositionOf(subject); ok {
+		return p, true
+	}
+	if res, ok := r.departed[subject]; ok && held {
+		return res.Position, true
+	}
+	return core.Position{}, false
+}
+
+// best returns the most interesting event this tick, earliest in
+// registration order among equals.
+func (d *Director) best(r *Runtime) shot {
+	if gone := r.departedAt(r.Tick()); len(gone) > 0 {
+		return shot{subject: gone[0].ID, pos: gone[0].Position, level: interestDeparture}
+	}
+	var out shot
+	for _, a := range r.agents {
+		id := a.ID()
+		pos, ok := r.world.PositionOf(id)
+		if !ok {
+			continue
+		}
+		level := interestNone
+		if r.previousAction(id) == agent.ATTACK || r.hasNeighbour(id, pos) {
+			level = interestConflict
+		} else if h, ok := a.(interface{ Hallucinations() int }); ok && h.Hallucinations() > 0 {
+			level = interestHallucination
+		}
+		if level > out.level {
+			out = shot{subject: id, pos: core.Position{X: pos.X, Y: pos.Y}, level: level}
+		}
+	}
+	return out
+}
+
+// hasNeighbour reports whether another active entity stands within one
+// step of pos, diagonals included.
+func (r *Runtime) hasNeighbour(id string, pos world.Position) bool {
+	for _, other := range r.agents {
+		if other.ID() == id {
+			continue
+		}
+		op, ok := r.world.PositionOf(other.ID())
+		if ok && util.Abs(op.X-pos.X) <= 1 && util.Abs(op.Y-pos.Y) <= 1 {
+			return true
+		}
+	}
+	return false
+}
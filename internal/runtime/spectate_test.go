@@ -0,0 +1,138 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+// seeingThings is a waiting agent that reports a fixed hallucination count.
+type seeingThings struct {
+	simpleAgent
+	n int
+}
+
+func (s *seeingThings) Hallucinations() int { return s.n }
+
+func TestFrame_ShowsWholeStageAndHiddenEntities(t *testing.T) {
+	rt := New([]agent.Agent{
+		&simpleAgent{id: "A", act: agent.WAIT},
+		&simpleAgent{id: "H", act: agent.HIDE},
+	})
+	rt.world.SetPosition("A", world.Position{X: 10, Y: 10})
+	rt.world.SetPosition("H", world.Position{X: 40, Y: 20})
+	rt.world.SetTerrain(world.Position{X: 3, Y: 4}, world.Wall)
+	rt.TickOnce()
+
+	f := rt.Frame()
+	if f.Tick != 1 || f.State != "running" || f.Width != world.Width || f.Height != world.Height {
+		t.Fatalf("frame header = %d %s %dx%d", f.Tick, f.State, f.Width, f.Height)
+	}
+	if len(f.Rows) != world.Height || len(f.Rows[0]) != world.Width {
+		t.Fatalf("frame is %d rows of %d", len(f.Rows), len(f.Rows[0]))
+	}
+	if g := f.Rows[4][3]; g != byte(world.GlyphWall) {
+		t.Fatalf("wall drawn as %q", g)
+	}
+	if g := f.Rows[10][10]; g != GlyphFrameFloor {
+		t.Fatalf("floor drawn as %q", g)
+	}
+	mp := rt.MarkerPosition()
+	if g := f.Rows[mp.Y][mp.X]; g != byte(world.GlyphMarker) {
+		t.Fatalf("marker drawn as %q", g)
+	}
+	want := []FrameEntity{
+		{ID: "A", Position: core.Position{X: 10, Y: 10}},
+		{ID: "H", Position: core.Position{X: 40, Y: 20}, Hidden: true},
+	}
+	if len(f.Entities) != len(want) || f.Entities[0] != want[0] || f.Entities[1] != want[1] {
+		t.Fatalf("entities = %+v, want %+v", f.Entities, want)
+	}
+}
+
+func TestRuntime_OnTickRunsAfterEachTick(t *testing.T) {
+	rt := New([]agent.Agent{&simpleAgent{id: "A", act: agent.WAIT}})
+	rt.AddWinCondition(TickLimit{Ticks: 2})
+	var seen []int
+	rt.OnTick(func(r *Runtime) { seen = append(seen, r.Tick()) })
+	for i := 0; i < 4; i++ {
+		rt.TickOnce()
+	}
+	if len(seen) != 2 || seen[0] != 1 || seen[1] != 2 {
+		t.Fatalf("OnTick saw ticks %v, want [1 2]", seen)
+	}
+}
+
+func TestDirector_FollowsFirstEntityWhenQuiet(t *testing.T) {
+	rt := New([]agent.Agent{
+		&simpleAgent{id: "A", act: agent.WAIT},
+		&simpleAgent{id: "B", act: agent.WAIT},
+	})
+	rt.world.SetPosition("B", world.Position{X: 30, Y: 10})
+	rt.TickOnce()
+	cam := NewDirector().Update(rt)
+	if cam.Subject != "A" || cam.Reason != "follow" || cam.Position != (core.Position{X: 0, Y: 0}) {
+		t.Fatalf("camera = %+v", cam)
+	}
+}
+
+func TestDirector_PrefersConflictAndHoldsShot(t *testing.T) {
+	rt := New([]agent.Agent{
+		&seeingThings{simpleAgent{id: "S", act: agent.WAIT}, 3},
+		&simpleAgent{id: "A", act: agent.ATTACK},
+		&simpleAgent{id: "W", act: agent.WAIT},
+	})
+	rt.world.SetPosition("S", world.Position{X: 5, Y: 5})
+	rt.world.SetPosition("A", world.Position{X: 20, Y: 5})
+	rt.world.SetPosition("W", world.Position{X: 40, Y: 5})
+	d := NewDirector()
+
+	rt.TickOnce()
+	if cam := d.Update(rt); cam.Subject != "A" || cam.Reason != "conflict" {
+		t.Fatalf("camera = %+v, want conflict on A", cam)
+	}
+
+	// A conflict of equal interest elsewhere does not steal a held shot.
+	rt.world.SetPosition("W", world.Position{X: 6, Y: 5})
+	rt.TickOnce()
+	if cam := d.Update(rt); cam.Subject != "A" {
+		t.Fatalf("camera cut to %+v within dwell", cam)
+	}
+}
+
+func TestDirector_CutsToDepartureAndLingers(t *testing.T) {
+	rt := New([]agent.Agent{
+		agent.NewScripted("E"), // walks east onto the exit
+		&seeingThings{simpleAgent{id: "S", act: agent.WAIT}, 1},
+	})
+	rt.world.SetPosition("S", world.Position{X: 30, Y: 10})
+	exit := world.Position{X: 2, Y: 0}
+	rt.world.AddExit(exit)
+	rt.world.SetExit(0, world.Exit{Position: exit, Open: true, NextToggle: 1000})
+	d := NewDirector()
+
+	rt.TickOnce()
+	if cam := d.Update(rt); cam.Subject != "S" || cam.Reason != "hallucination" {
+		t.Fatalf("camera = %+v, want hallucination on S", cam)
+	}
+	rt.TickOnce()
+	if f := rt.Frame(); len(f.Departed) != 1 || f.Departed[0].ID != "E" {
+		t.Fatalf("departed = %+v", f.Departed)
+	}
+	cam := d.Update(rt)
+	if cam.Subject != "E" || cam.Reason != "departure" || cam.Position != (core.Position{X: exit.X, Y: exit.Y}) {
+		t.Fatalf("camera = %+v, want departure of E at the exit", cam)
+	}
+	rt.TickOnce()
+	if cam := d.Update(rt); cam.Subject != "E" {
+		t.Fatalf("camera left the departure early: %+v", cam)
+	}
+	for i := 0; i < DefaultDwell; i++ {
+		rt.TickOnce()
+	}
+	if cam := d.Update(rt); cam.Subject != "S" {
+		t.Fatalf("camera after dwell = %+v, want S", cam)
+	}
+}
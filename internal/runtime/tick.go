@@ -1,18 +1,33 @@
 package runtime
 
 import (
+	"hash/fnv"
+	"sort"
 	"time"
 
 	"github.com/divijg19/Nightshade/internal/agent"
 	"github.com/divijg19/Nightshade/internal/core"
 	"github.com/divijg19/Nightshade/internal/game"
+	"github.com/divijg19/Nightshade/internal/util"
+	"github.com/divijg19/Nightshade/internal/world"
 )
 
 type Decisions map[string]agent.Action
 
+// TickOnce advances the run by one tick. The first call on a run still in
+// the lobby starts it; calls after the run has ended do nothing and return
+// no decisions.
 func (r *Runtime) TickOnce() Decisions {
+	if r.state == RunEnded {
+		return Decisions{}
+	}
+	r.Start()
+	started := time.Now()
+
 	// Advance non-agent world facts before agents observe.
 	r.world.MoveMarker()
+	game.UpdateObjects(r.world, r.tick)
+	game.UpdateExits(r.world, r.tick, r.rng, game.DefaultExitTiming)
 
 	decisions := make(Decisions)
 
@@ -20,7 +35,7 @@ func (r *Runtime) TickOnce() Decisions {
 	//    RemoteHuman agents via their Observe/SendObservation channels.
 	snaps := make(map[string]Snapshot)
 	for _, a := range r.agents {
-		preSnap := r.snapshotFor(a, agent.Action(-1))
+		preSnap := r.snapshotFor(a, r.previousAction(a.ID()))
 		snaps[a.ID()] = preSnap
 		if rh, ok := a.(*agent.RemoteHuman); ok {
 			// Non-blocking notify the agent of the snapshot (agent will build
@@ -32,7 +47,7 @@ func (r *Runtime) TickOnce() Decisions {
 	// 2. Input phase: collect exactly one input per connected RemoteHuman.
 	//    We use a bounded timeout to avoid indefinite blocking.
 	inputs := make(map[string]string)
-	inputTimeout := 200 * time.Millisecond
+	inputTimeout := r.cfg.InputTimeout
 	for _, a := range r.agents {
 		if rh, ok := a.(*agent.RemoteHuman); ok {
 			// Attempt to read one input for this agent with timeout.
@@ -41,6 +56,8 @@ func (r *Runtime) TickOnce() Decisions {
 				inputs[a.ID()] = in
 			case <-time.After(inputTimeout):
 				inputs[a.ID()] = ""
+				r.metrics.InputTimeouts.Inc()
+				r.log().Debug("no input before timeout", "agent", a.ID(), "timeout", inputTimeout)
 			}
 		} else {
 			inputs[a.ID()] = ""
@@ -67,38 +84,60 @@ func (r *Runtime) TickOnce() Decisions {
 			action = a.Decide(preSnap)
 		}
 		decisions[a.ID()] = action
+		r.lastActions[a.ID()] = action
+		r.recordDecision(a)
 
 		// 4. Resolution: apply movement results to world
 		pos, ok := r.world.PositionOf(a.ID())
 		if !ok {
 			continue
 		}
-		newPos := game.ResolveMovement(
-			pos,
-			action,
-			r.world.Width(),
-			r.world.Height(),
-		)
+		newPos := game.ResolveMove(r.world, pos, action)
 		r.world.SetPosition(a.ID(), newPos)
 	}
 
 	// 5. Advance the runtime tick counter
 	r.advanceTick()
+
+	// 6. Extraction: entities standing on an open exit leave the run. Collect
+	// first so removal does not disturb iteration.
+	extracted := []string{}
+	for _, a := range r.agents {
+		if pos, ok := r.world.PositionOf(a.ID()); ok && game.Extracted(r.world, pos) {
+			extracted = append(extracted, a.ID())
+		}
+	}
+	for _, id := range extracted {
+		r.remove(id, OutcomeExtracted)
+	}
+
+	// 7. Control: credit zone holders with influence for this tick
+	r.accountZones()
+
+	// 8. Evaluate win conditions against the resolved state
+	r.evaluateWinConditions()
+
+	r.recordTick(started, decisions)
+
+	// 9. Notify tick observers (spectators) of the resolved state
+	if r.onTick != nil {
+		r.onTick(r)
+	}
 	return decisions
 }
 
-func (r *Runtime) snapshotFor(a agent.Agent, action agent.Action) Snapshot {
+// snapshotFor builds the snapshot entity a perceives this tick. prev is the
+// action a resolved on the previous tick (or -1 before its first action);
+// OBSERVE widens the view and reveals concealed entities in range, and the
+// noise other entities made resolving their previous actions is reported as
+// sound cues.
+func (r *Runtime) snapshotFor(a agent.Agent, prev agent.Action) Snapshot {
 	snap := Snapshot{
 		Tick:   r.tick,
 		SelfID: a.ID(),
+		Seed:   r.cognitionSeed(a.ID()),
 	}
 
-	// Use the `action` parameter to avoid unused parameter linter warnings.
-	// The runtime does not change visibility based on actions (OBSERVE
-	// semantics are agent-layer only), so we only reference the value
-	// harmlessly here to preserve the current API.
-	_ = action
-
 	pos, ok := r.world.PositionOf(a.ID())
 	if !ok {
 		return snap
@@ -107,29 +146,122 @@ func (r *Runtime) snapshotFor(a agent.Agent, action agent.Action) Snapshot {
 		X: pos.X,
 		Y: pos.Y,
 	}
-	radius := defaultVisibilityRadius
+	radius := r.visibilityRadius(a, pos, prev)
+	snap.VisibilityRadius = radius
 
-	markerPos := r.world.MarkerPosition()
 	snap.Visible = computeVisibleTiles(
 		pos.X,
 		pos.Y,
 		r.world.Width(),
 		r.world.Height(),
 		radius,
-		markerPos.X,
-		markerPos.Y,
+		r.world.GlyphAt,
 	)
+	snap.Entities = r.visibleEntities(a.ID(), pos, radius, prev)
+	snap.Sounds = r.heardSounds(a.ID(), pos, snap.Entities)
 	// Do NOT populate snap.Known here. Known is the agent's interpretation
 	// (belief) and must be maintained by the agent's Memory. Runtime reports
 	// only current visibility in Snapshot.Visible.
 	return snap
 }
 
+// cognitionSeed returns the seed entity id draws its own randomness
+// (forgetting) from this tick. It depends only on the run seed, the tick
+// and the id, so entities never disturb each other's draws or the world's
+// RNG.
+func (r *Runtime) cognitionSeed(id string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return util.NewRand(r.cfg.Seed ^ h.Sum64() ^ uint64(r.tick)*0x9e3779b97f4a7c15).Uint64()
+}
+
+// previousAction returns the action id resolved last tick, or -1 if it has
+// not acted yet.
+func (r *Runtime) previousAction(id string) agent.Action {
+	if act, ok := r.lastActions[id]; ok {
+		return act
+	}
+	return agent.Action(-1)
+}
+
+// visibilityRadius applies perception modifiers to the configured radius
+// for entity a standing at pos. Agents that do not expose energy are treated
+// as fully rested.
+func (r *Runtime) visibilityRadius(a agent.Agent, pos world.Position, prev agent.Action) int {
+	p := game.Perceiver{
+		LastAction: prev,
+		Energy:     agent.MaxEnergy,
+		Terrain:    r.world.Terrain(pos),
+	}
+	if e, ok := a.(interface{ Energy() int }); ok {
+		p.Energy = e.Energy()
+	}
+	return game.VisibilityRadius(r.cfg.VisibilityRadius, p)
+}
+
+// visibleEntities returns the other active entities within radius of pos.
+// Concealed entities are included only when the observer's previous action
+// reveals them. The result is ordered by position so snapshots are
+// deterministic.
+func (r *Runtime) visibleEntities(selfID string, pos world.Position, radius int, prev agent.Action) []core.EntityView {
+	out := []core.EntityView{}
+	for _, other := range r.agents {
+		id := other.ID()
+		if id == selfID {
+			continue
+		}
+		op, ok := r.world.PositionOf(id)
+		if !ok || util.Abs(op.X-pos.X) > radius || util.Abs(op.Y-pos.Y) > radius {
+			continue
+		}
+		if game.Concealed(r.previousAction(id), r.world.Terrain(op)) && !game.Reveals(prev) {
+			continue
+		}
+		out = append(out, core.EntityView{ID: id, Position: core.Position{X: op.X, Y: op.Y}})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].Position, out[j].Position
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// heardSounds returns the noise cues reaching pos from last tick's actions.
+// Entities the observer can already see make no cue; sound only carries
+// what sight does not. Cues follow roster order.
+func (r *Runtime) heardSounds(selfID string, pos world.Position, seen []core.EntityView) []core.SoundCue {
+	visible := map[string]bool{}
+	for _, e := range seen {
+		visible[e.ID] = true
+	}
+	out := []core.SoundCue{}
+	for _, other := range r.agents {
+		id := other.ID()
+		if id == selfID || visible[id] {
+			continue
+		}
+		op, ok := r.world.PositionOf(id)
+		if !ok {
+			continue
+		}
+		if cue, ok := game.Hear(pos, op, r.previousAction(id)); ok {
+			out = append(out, cue)
+		}
+	}
+	return out
+}
+
 func computeVisibleTiles(
 	ax, ay int,
 	worldWidth, worldHeight int,
 	radius int,
-	markerX, markerY int,
+	glyphAt func(world.Position) rune,
 ) []core.TileView {
 	tiles := []core.TileView{}
 
@@ -142,12 +274,9 @@ func computeVisibleTiles(
 				continue
 			}
 
-			glyph := rune(0)
-			// Reveal marker if within visibility by comparing to
-			// the passed-in marker coordinates.
-			if markerX == x && markerY == y {
-				glyph = 'M'
-			}
+			// The world decides what is shown at each position
+			// (marker, open exits, floor).
+			glyph := glyphAt(world.Position{X: x, Y: y})
 			tiles = append(tiles, core.TileView{
 				Position: core.Position{X: x, Y: y},
 				Glyph:    glyph,
@@ -0,0 +1,107 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/core"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+func TestSnapshot_ReportsEffectiveRadius(t *testing.T) {
+	obs := &simpleAgent{id: "O", act: agent.OBSERVE}
+	hide := &simpleAgent{id: "H", act: agent.HIDE}
+	rt := New([]agent.Agent{obs, hide})
+	rt.world.SetPosition("O", world.Position{X: 10, Y: 10})
+	rt.world.SetPosition("H", world.Position{X: 20, Y: 10})
+
+	// Before anyone has acted both see the configured radius.
+	for _, id := range []string{"O", "H"} {
+		snap, _ := rt.SnapshotForDebug(id)
+		if snap.VisibilityRadius != defaultVisibilityRadius {
+			t.Fatalf("%s initial radius = %d", id, snap.VisibilityRadius)
+		}
+	}
+
+	rt.TickOnce()
+	so, _ := rt.SnapshotForDebug("O")
+	sh, _ := rt.SnapshotForDebug("H")
+	if so.VisibilityRadius != defaultVisibilityRadius+1 || len(so.Visible) != 7*7 {
+		t.Fatalf("observer radius = %d with %d tiles", so.VisibilityRadius, len(so.Visible))
+	}
+	if sh.VisibilityRadius != defaultVisibilityRadius-1 || len(sh.Visible) != 3*3 {
+		t.Fatalf("hider radius = %d with %d tiles", sh.VisibilityRadius, len(sh.Visible))
+	}
+}
+
+func TestSnapshot_DarknessNarrowsView(t *testing.T) {
+	rt := New([]agent.Agent{&simpleAgent{id: "A", act: agent.WAIT}})
+	pos := world.Position{X: 5, Y: 5}
+	rt.world.SetPosition("A", pos)
+	rt.world.SetTerrain(pos, world.Dark)
+	snap, _ := rt.SnapshotForDebug("A")
+	if snap.VisibilityRadius != defaultVisibilityRadius-1 {
+		t.Fatalf("radius in darkness = %d", snap.VisibilityRadius)
+	}
+}
+
+func TestSnapshot_ObserveRevealsHiddenEntities(t *testing.T) {
+	watcher := &simpleAgent{id: "W", act: agent.WAIT}
+	hider := &simpleAgent{id: "H", act: agent.HIDE}
+	rt := New([]agent.Agent{watcher, hider})
+	rt.world.SetPosition("W", world.Position{X: 10, Y: 10})
+	rt.world.SetPosition("H", world.Position{X: 11, Y: 10})
+
+	// Before the hider has acted it stands in the open.
+	snap, _ := rt.SnapshotForDebug("W")
+	if len(snap.Entities) != 1 || snap.Entities[0].ID != "H" {
+		t.Fatalf("initial entities = %+v", snap.Entities)
+	}
+
+	rt.TickOnce()
+	snap, _ = rt.SnapshotForDebug("W")
+	if len(snap.Entities) != 0 {
+		t.Fatalf("hidden entity visible to waiting observer: %+v", snap.Entities)
+	}
+
+	watcher.act = agent.OBSERVE
+	rt.TickOnce()
+	snap, _ = rt.SnapshotForDebug("W")
+	if len(snap.Entities) != 1 || snap.Entities[0].Position.X != 11 {
+		t.Fatalf("observing did not reveal hider: %+v", snap.Entities)
+	}
+}
+
+func TestSnapshot_EntitiesOutsideRadiusUnseen(t *testing.T) {
+	rt := New([]agent.Agent{
+		&simpleAgent{id: "A", act: agent.WAIT},
+		&simpleAgent{id: "B", act: agent.WAIT},
+	})
+	rt.world.SetPosition("A", world.Position{X: 10, Y: 10})
+	rt.world.SetPosition("B", world.Position{X: 10 + defaultVisibilityRadius + 1, Y: 10})
+	snap, _ := rt.SnapshotForDebug("A")
+	if len(snap.Entities) != 0 {
+		t.Fatalf("entity beyond radius visible: %+v", snap.Entities)
+	}
+}
+
+func TestSnapshot_HearsUnseenMovement(t *testing.T) {
+	rt := New([]agent.Agent{
+		&simpleAgent{id: "L", act: agent.WAIT},
+		&simpleAgent{id: "F", act: agent.MOVE_S},
+		&simpleAgent{id: "N", act: agent.MOVE_N},
+	})
+	rt.world.SetPosition("L", world.Position{X: 20, Y: 10})
+	rt.world.SetPosition("F", world.Position{X: 16, Y: 9})
+	rt.world.SetPosition("N", world.Position{X: 21, Y: 11})
+	rt.TickOnce()
+
+	// F walked out of sight and is heard; N is in plain view and is not.
+	snap, _ := rt.SnapshotForDebug("L")
+	if len(snap.Sounds) != 1 {
+		t.Fatalf("sounds = %+v", snap.Sounds)
+	}
+	if c := snap.Sounds[0]; c.Kind != core.SoundFootsteps || c.Direction != "west" {
+		t.Fatalf("unexpected cue %+v", c)
+	}
+}
@@ -0,0 +1,85 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/agent"
+	"github.com/divijg19/Nightshade/internal/util"
+	"github.com/divijg19/Nightshade/internal/world"
+)
+
+func TestNewWithConfig_PlacesAgentsOnSpawns(t *testing.T) {
+	w, err := world.Generate(world.LayoutRooms, world.Width, world.Height, util.NewRand(3), world.DefaultGenOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spawns := w.Spawns()
+	cfg := DefaultConfig()
+	cfg.World = w
+	rt, err := NewWithConfig([]agent.Agent{agent.NewScripted("A"), agent.NewScripted("B")}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range []string{"A", "B"} {
+		pos, ok := rt.world.PositionOf(id)
+		if !ok || pos != spawns[i] {
+			t.Fatalf("%s placed at %+v, want spawn %+v", id, pos, spawns[i])
+		}
+	}
+}
+
+func TestNewWithConfig_FallsBackToFreeFloor(t *testing.T) {
+	w := world.New(4, 3)
+	w.SetTerrain(world.Position{X: 0, Y: 0}, world.Wall)
+	w.AddSpawn(world.Position{X: 2, Y: 2})
+	cfg := DefaultConfig()
+	cfg.World = w
+	rt, err := NewWithConfig([]agent.Agent{
+		&simpleAgent{id: "A", act: agent.WAIT},
+		&simpleAgent{id: "B", act: agent.WAIT},
+		&simpleAgent{id: "C", act: agent.WAIT},
+	}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]world.Position{
+		"A": {X: 2, Y: 2}, // spawn point
+		"B": {X: 1, Y: 0}, // first free floor after the wall
+		"C": {X: 2, Y: 0},
+	}
+	for id, p := range want {
+		if got, _ := rt.world.PositionOf(id); got != p {
+			t.Fatalf("%s at %+v, want %+v", id, got, p)
+		}
+	}
+}
+
+func TestTickOnce_WallsBlockMovement(t *testing.T) {
+	a := &simpleAgent{id: "A", act: agent.MOVE_E}
+	rt := New([]agent.Agent{a})
+	rt.world.SetTerrain(world.Position{X: 1, Y: 0}, world.Wall)
+	rt.TickOnce()
+	if pos, _ := rt.world.PositionOf("A"); pos != (world.Position{X: 0, Y: 0}) {
+		t.Fatalf("agent walked through a wall: %+v", pos)
+	}
+}
+
+func TestTickOnce_ObjectsMoveBeforeObservation(t *testing.T) {
+	w := world.New(10, 10)
+	w.AddObject(world.Object{Glyph: world.GlyphLight, Position: world.Position{X: 4, Y: 1}, Behavior: world.Drift, Dir: world.Position{X: 1}})
+	cfg := DefaultConfig()
+	cfg.World = w
+	rt, err := NewWithConfig([]agent.Agent{&simpleAgent{id: "A", act: agent.WAIT}}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt.world.SetPosition("A", world.Position{X: 5, Y: 2})
+	rt.TickOnce()
+	snap, _ := rt.SnapshotForDebug("A")
+	for _, tile := range snap.Visible {
+		if tile.Glyph == world.GlyphLight && tile.Position.X == 5 && tile.Position.Y == 1 {
+			return
+		}
+	}
+	t.Fatal("drifting light not seen at its updated position")
+}
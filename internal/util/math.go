@@ -1 +1,9 @@
 package util
+
+// Abs returns the absolute value of v.
+func Abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -1 +1,45 @@
 package util
+
+// Rand is a small deterministic pseudo-random generator (SplitMix64). It is
+// used for every random choice made by the simulation so a run can be
+// replayed exactly from its seed. The algorithm is trivial to mirror in the
+// offline Python tooling. Rand is not safe for concurrent use; it belongs to
+// the goroutine that owns the world.
+type Rand struct {
+	state uint64
+}
+
+// NewRand returns a generator seeded with seed.
+func NewRand(seed uint64) *Rand {
+	return &Rand{state: seed}
+}
+
+// Uint64 returns the next 64-bit value in the sequence.
+func (r *Rand) Uint64() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Intn returns a value in [0, n). It returns 0 when n <= 0.
+func (r *Rand) Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return int(r.Uint64() % uint64(n))
+}
+
+// Range returns a value in [lo, hi]. It returns lo when hi < lo.
+func (r *Rand) Range(lo, hi int) int {
+	if hi < lo {
+		return lo
+	}
+	return lo + r.Intn(hi-lo+1)
+}
+
+// Float64 returns a value in [0, 1).
+func (r *Rand) Float64() float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
@@ -0,0 +1,45 @@
+package world
+
+// Exit is a fixed extraction point. Exits open and close over the course of
+// a run; an entity standing on an open exit leaves the run. NextToggle is the
+// tick at which the exit next changes state and is maintained by the game
+// rules, not by World.
+type Exit struct {
+	Position   Position
+	Open       bool
+	NextToggle int
+}
+
+// AddExit places a closed exit at pos. Adding an exit where one already
+// exists has no effect.
+func (w *World) AddExit(pos Position) {
+	if _, ok := w.ExitAt(pos); ok {
+		return
+	}
+	w.exits = append(w.exits, Exit{Position: pos})
+}
+
+// Exits returns a copy of all exits in placement order.
+func (w *World) Exits() []Exit {
+	out := make([]Exit, len(w.exits))
+	copy(out, w.exits)
+	return out
+}
+
+// SetExit replaces the exit at index i. Out-of-range indices are ignored.
+func (w *World) SetExit(i int, e Exit) {
+	if i < 0 || i >= len(w.exits) {
+		return
+	}
+	w.exits[i] = e
+}
+
+// ExitAt returns the exit at pos, if any.
+func (w *World) ExitAt(pos Position) (Exit, bool) {
+	for _, e := range w.exits {
+		if e.Position == pos {
+			return e, true
+		}
+	}
+	return Exit{}, false
+}
@@ -0,0 +1,365 @@
+package world
+
+import (
+	"fmt"
+
+	"github.com/divijg19/Nightshade/internal/util"
+)
+
+// Layout selects a procedural terrain generator.
+type Layout string
+
+const (
+	// LayoutEmpty is an open rectangle with no walls (the original stage).
+	LayoutEmpty Layout = "empty"
+	// LayoutRooms carves rectangular rooms joined by corridors.
+	LayoutRooms Layout = "rooms"
+	// LayoutCaves grows organic caverns with a cellular automaton.
+	LayoutCaves Layout = "caves"
+	// LayoutArena is a walled open floor scattered with pillars.
+	LayoutArena Layout = "arena"
+)
+
+// Layouts lists every supported layout in a stable order.
+var Layouts = []Layout{LayoutEmpty, LayoutRooms, LayoutCaves, LayoutArena}
+
+// ParseLayout converts a layout name into a Layout.
+func ParseLayout(s string) (Layout, error) {
+	for _, l := range Layouts {
+		if string(l) == s {
+			return l, nil
+		}
+	}
+	return "", fmt.Errorf("unknown layout %q", s)
+}
+
+// GenOptions controls what is placed on the floor once terrain is carved.
+type GenOptions struct {
+	Spawns    int
+	Exits     int
+	Resources int
+
+	// Objects is the number of dynamic objects to place. Kinds cycle through
+	// sentry, beacon, light and moving wall.
+	Objects int
+}
+
+// DefaultGenOptions is used by the binaries when no options are given.
+var DefaultGenOptions = GenOptions{Spawns: 8, Exits: 2, Resources: 12, Objects: 8}
+
+// Generate builds a world of the given size using layout. All randomness is
+// drawn from rng, so the same seed always yields the same stage. Every floor
+// cell of the result is reachable from every other, and spawns, exits and
+// resources are placed on distinct floor cells.
+func Generate(layout Layout, width, height int, rng *util.Rand, opts GenOptions) (*World, error) {
+	w := New(width, height)
+	if width < 3 || height < 3 {
+		// Too small to wall off; keep the open floor.
+		layout = LayoutEmpty
+	}
+	switch layout {
+	case LayoutEmpty:
+	case LayoutRooms:
+		carveRooms(w.grid, rng)
+	case LayoutCaves:
+		carveCaves(w.grid, rng)
+	case LayoutArena:
+		carveArena(w.grid, rng)
+	default:
+		return nil, fmt.Errorf("unknown layout %q", layout)
+	}
+	keepLargestRegion(w.grid)
+
+	floor := floorCells(w.grid)
+	pick := func() (Position, bool) {
+		if len(floor) == 0 {
+			return Position{}, false
+		}
+		i := rng.Intn(len(floor))
+		p := floor[i]
+		floor[i] = floor[len(floor)-1]
+		floor = floor[:len(floor)-1]
+		return p, true
+	}
+	for i := 0; i < opts.Spawns; i++ {
+		if p, ok := pick(); ok {
+			w.AddSpawn(p)
+		}
+	}
+	for i := 0; i < opts.Exits; i++ {
+		if p, ok := pick(); ok {
+			w.AddExit(p)
+		}
+	}
+	for i := 0; i < opts.Resources; i++ {
+		if p, ok := pick(); ok {
+			w.AddResource(p)
+		}
+	}
+	// Objects are placed last so adding them does not disturb the placement
+	// of anything above for a given seed.
+	for i := 0; i < opts.Objects; i++ {
+		p, ok := pick()
+		if !ok {
+			break
+		}
+		w.AddObject(newObject(w, i, p, pick, rng))
+	}
+	return w, nil
+}
+
+// newObject builds the i-th generated object at p. Sentries patrol to a
+// second picked cell; moving walls slide along a straight run of floor.
+func newObject(w *World, i int, p Position, pick func() (Position, bool), rng *util.Rand) Object {
+	switch i % 4 {
+	case 0:
+		path := []Position{p}
+		if q, ok := pick(); ok {
+			path = append(path, q)
+		}
+		return Object{Name: "sentry", Glyph: GlyphSentry, Position: p, Behavior: Patrol, Period: 2, Path: path}
+	case 1:
+		return Object{Name: "beacon", Glyph: GlyphBeacon, Position: p, Behavior: Blink, Period: 3}
+	case 2:
+		dirs := []Position{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}}
+		return Object{Name: "light", Glyph: GlyphLight, Position: p, Behavior: Drift, Period: 2, Dir: dirs[rng.Intn(len(dirs))]}
+	default:
+		end := p
+		for _, d := range []Position{{X: 1}, {Y: 1}} {
+			for n := 0; n < 4; n++ {
+				next := Position{X: end.X + d.X, Y: end.Y + d.Y}
+				if !w.grid.InBounds(next) || w.grid.At(next) == Wall {
+					break
+				}
+				end = next
+			}
+			if end != p {
+				break
+			}
+		}
+		return Object{Name: "moving wall", Glyph: GlyphWall, Position: p, Behavior: Patrol, Period: 3, Path: []Position{p, end}, Solid: true}
+	}
+}
+
+type rect struct{ x, y, w, h int }
+
+func (r rect) center() Position { return Position{X: r.x + r.w/2, Y: r.y + r.h/2} }
+
+// overlaps reports whether r and o intersect once r is grown by one cell,
+// which keeps a wall between neighbouring rooms.
+func (r rect) overlaps(o rect) bool {
+	return r.x-1 < o.x+o.w && o.x < r.x+r.w+1 && r.y-1 < o.y+o.h && o.y < r.y+r.h+1
+}
+
+// carveRooms fills the grid with wall, carves non-overlapping rooms and joins
+// each room to the previous one with an L-shaped corridor.
+func carveRooms(g *Grid, rng *util.Rand) {
+	fill(g, Wall)
+	maxRooms := g.width * g.height / 150
+	if maxRooms < 4 {
+		maxRooms = 4
+	}
+	rooms := []rect{}
+	for attempt := 0; attempt < maxRooms*20 && len(rooms) < maxRooms; attempt++ {
+		rw := rng.Range(4, 12)
+		rh := rng.Range(3, 7)
+		if rw > g.width-2 {
+			rw = g.width - 2
+		}
+		if rh > g.height-2 {
+			rh = g.height - 2
+		}
+		r := rect{x: rng.Range(1, g.width-rw-1), y: rng.Range(1, g.height-rh-1), w: rw, h: rh}
+		clash := false
+		for _, o := range rooms {
+			if r.overlaps(o) {
+				clash = true
+				break
+			}
+		}
+		if clash {
+			continue
+		}
+		for y := r.y; y < r.y+r.h; y++ {
+			for x := r.x; x < r.x+r.w; x++ {
+				g.Set(Position{X: x, Y: y}, Floor)
+			}
+		}
+		if n := len(rooms); n > 0 {
+			carveCorridor(g, rooms[n-1].center(), r.center(), rng.Intn(2) == 0)
+		}
+		rooms = append(rooms, r)
+	}
+}
+
+// carveCorridor digs an L-shaped corridor from a to b, horizontal leg first
+// when horizontalFirst is set.
+func carveCorridor(g *Grid, a, b Position, horizontalFirst bool) {
+	corner := Position{X: b.X, Y: a.Y}
+	if !horizontalFirst {
+		corner = Position{X: a.X, Y: b.Y}
+	}
+	carveLine(g, a, corner)
+	carveLine(g, corner, b)
+}
+
+func carveLine(g *Grid, a, b Position) {
+	dx, dy := sign(b.X-a.X), sign(b.Y-a.Y)
+	for p := a; ; p = (Position{X: p.X + dx, Y: p.Y + dy}) {
+		g.Set(p, Floor)
+		if p == b {
+			return
+		}
+	}
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// carveCaves seeds the interior with random wall and smooths it with a
+// cellular automaton: a cell becomes wall when more than four of its eight
+// neighbours are wall and floor when fewer than four are.
+func carveCaves(g *Grid, rng *util.Rand) {
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Position{X: x, Y: y}
+			if onBorder(g, p) || rng.Float64() < 0.45 {
+				g.Set(p, Wall)
+			} else {
+				g.Set(p, Floor)
+			}
+		}
+	}
+	for step := 0; step < 4; step++ {
+		next := make([]Terrain, len(g.cells))
+		copy(next, g.cells)
+		for y := 1; y < g.height-1; y++ {
+			for x := 1; x < g.width-1; x++ {
+				n := wallNeighbours(g, Position{X: x, Y: y})
+				switch {
+				case n > 4:
+					next[y*g.width+x] = Wall
+				case n < 4:
+					next[y*g.width+x] = Floor
+				}
+			}
+		}
+		g.cells = next
+	}
+}
+
+func wallNeighbours(g *Grid, p Position) int {
+	n := 0
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if (dx != 0 || dy != 0) && g.At(Position{X: p.X + dx, Y: p.Y + dy}) == Wall {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// carveArena walls the border and scatters single-cell pillars over the
+// open interior.
+func carveArena(g *Grid, rng *util.Rand) {
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Position{X: x, Y: y}
+			if onBorder(g, p) {
+				g.Set(p, Wall)
+			}
+		}
+	}
+	pillars := g.width * g.height / 60
+	for i := 0; i < pillars; i++ {
+		g.Set(Position{X: rng.Range(2, g.width-3), Y: rng.Range(2, g.height-3)}, Wall)
+	}
+}
+
+func onBorder(g *Grid, p Position) bool {
+	return p.X == 0 || p.Y == 0 || p.X == g.width-1 || p.Y == g.height-1
+}
+
+func fill(g *Grid, t Terrain) {
+	for i := range g.cells {
+		g.cells[i] = t
+	}
+}
+
+// floorCells returns every passable cell (floor or darkness) in row-major
+// order.
+func floorCells(g *Grid) []Position {
+	out := []Position{}
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Position{X: x, Y: y}
+			if g.At(p) != Wall {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// Region returns every passable cell 4-connected to start, including start.
+// It returns nil when start is a wall.
+func (g *Grid) Region(start Position) []Position {
+	if g.At(start) == Wall {
+		return nil
+	}
+	seen := map[Position]bool{start: true}
+	queue := []Position{start}
+	for i := 0; i < len(queue); i++ {
+		p := queue[i]
+		for _, n := range []Position{{X: p.X, Y: p.Y - 1}, {X: p.X, Y: p.Y + 1}, {X: p.X + 1, Y: p.Y}, {X: p.X - 1, Y: p.Y}} {
+			if !seen[n] && g.At(n) != Wall {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return queue
+}
+
+// keepLargestRegion walls off every floor region except the largest so the
+// remaining floor is fully connected. Ties go to the region found first in
+// row-major order. A grid with no floor gets a single floor cell at its
+// center.
+func keepLargestRegion(g *Grid) {
+	seen := map[Position]bool{}
+	var best []Position
+	for _, p := range floorCells(g) {
+		if seen[p] {
+			continue
+		}
+		region := g.Region(p)
+		for _, q := range region {
+			seen[q] = true
+		}
+		if len(region) > len(best) {
+			best = region
+		}
+	}
+	if best == nil {
+		g.Set(Position{X: g.width / 2, Y: g.height / 2}, Floor)
+		return
+	}
+	keep := make(map[Position]bool, len(best))
+	for _, p := range best {
+		keep[p] = true
+	}
+	for _, p := range floorCells(g) {
+		if !keep[p] {
+			g.Set(p, Wall)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package world
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/divijg19/Nightshade/internal/util"
+)
+
+func generate(t *testing.T, layout Layout, seed uint64) *World {
+	t.Helper()
+	w, err := Generate(layout, Width, Height, util.NewRand(seed), DefaultGenOptions)
+	if err != nil {
+		t.Fatalf("Generate(%s): %v", layout, err)
+	}
+	return w
+}
+
+func TestGenerate_DeterministicFromSeed(t *testing.T) {
+	for _, layout := range Layouts {
+		a := generate(t, layout, 99)
+		b := generate(t, layout, 99)
+		if !reflect.DeepEqual(a, b) {
+			t.Fatalf("%s: worlds differ for the same seed", layout)
+		}
+	}
+}
+
+func TestGenerate_SeedsProduceDifferentStages(t *testing.T) {
+	a := generate(t, LayoutCaves, 1)
+	b := generate(t, LayoutCaves, 2)
+	if reflect.DeepEqual(a.grid, b.grid) {
+		t.Fatal("different seeds produced identical caves")
+	}
+}
+
+func TestGenerate_FloorIsConnectedAndPlacementsOnFloor(t *testing.T) {
+	for _, layout := range Layouts {
+		for seed := uint64(1); seed <= 20; seed++ {
+			w := generate(t, layout, seed)
+			floor := floorCells(w.grid)
+			spawns := w.Spawns()
+			if len(spawns) != DefaultGenOptions.Spawns {
+				t.Fatalf("%s/%d: %d spawns, want %d", layout, seed, len(spawns), DefaultGenOptions.Spawns)
+			}
+			region := w.grid.Region(spawns[0])
+			if len(region) != len(floor) {
+				t.Fatalf("%s/%d: region %d of %d floor cells reachable", layout, seed, len(region), len(floor))
+			}
+			used := map[Position]bool{}
+			check := func(kind string, p Position) {
+				if !w.Passable(p) {
+					t.Fatalf("%s/%d: %s at %+v is not floor", layout, seed, kind, p)
+				}
+				if used[p] {
+					t.Fatalf("%s/%d: %s at %+v overlaps another placement", layout, seed, kind, p)
+				}
+				used[p] = true
+			}
+			if n := len(w.Objects()); n != DefaultGenOptions.Objects {
+				t.Fatalf("%s/%d: %d objects, want %d", layout, seed, n, DefaultGenOptions.Objects)
+			}
+			for _, p := range spawns {
+				check("spawn", p)
+			}
+			for _, e := range w.Exits() {
+				check("exit", e.Position)
+			}
+			for p := range w.resources {
+				check("resource", p)
+			}
+		}
+	}
+}
+
+func TestGenerate_WalledLayoutsHaveWalls(t *testing.T) {
+	for _, layout := range []Layout{LayoutRooms, LayoutCaves, LayoutArena} {
+		w := generate(t, layout, 5)
+		if w.Terrain(Position{X: 0, Y: 0}) != Wall {
+			t.Fatalf("%s: expected a wall at the corner", layout)
+		}
+		if w.GlyphAt(Position{X: 0, Y: 0}) != GlyphWall {
+			t.Fatalf("%s: wall glyph not reported", layout)
+		}
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	if l, err := ParseLayout("caves"); err != nil || l != LayoutCaves {
+		t.Fatalf("ParseLayout(caves) = %q, %v", l, err)
+	}
+	if _, err := ParseLayout("maze"); err == nil {
+		t.Fatal("expected error for unknown layout")
+	}
+}
@@ -1 +1,90 @@
 package world
+
+// Terrain is the static kind of a grid cell.
+type Terrain uint8
+
+const (
+	Floor Terrain = iota
+	Wall
+	// Dark is passable floor that narrows the view of whoever stands on it.
+	Dark
+)
+
+// Grid is a dense width x height terrain map stored row-major.
+type Grid struct {
+	width  int
+	height int
+	cells  []Terrain
+}
+
+// NewGrid returns a grid of the given size filled with fill.
+func NewGrid(width, height int, fill Terrain) *Grid {
+	g := &Grid{width: width, height: height, cells: make([]Terrain, width*height)}
+	if fill != Floor {
+		for i := range g.cells {
+			g.cells[i] = fill
+		}
+	}
+	return g
+}
+
+// InBounds reports whether pos lies on the grid.
+func (g *Grid) InBounds(pos Position) bool {
+	return pos.X >= 0 && pos.Y >= 0 && pos.X < g.width && pos.Y < g.height
+}
+
+// At returns the terrain at pos. Out-of-bounds positions read as Wall.
+func (g *Grid) At(pos Position) Terrain {
+	if !g.InBounds(pos) {
+		return Wall
+	}
+	return g.cells[pos.Y*g.width+pos.X]
+}
+
+// Set changes the terrain at pos. Out-of-bounds positions are ignored.
+func (g *Grid) Set(pos Position, t Terrain) {
+	if !g.InBounds(pos) {
+		return
+	}
+	g.cells[pos.Y*g.width+pos.X] = t
+}
+
+// Terrain returns the terrain at pos.
+func (w *World) Terrain(pos Position) Terrain {
+	return w.grid.At(pos)
+}
+
+// SetTerrain changes the terrain at pos.
+func (w *World) SetTerrain(pos Position, t Terrain) {
+	w.grid.Set(pos, t)
+}
+
+// Passable reports whether an entity may stand at pos: the cell must be on
+// the grid, not wall and not blocked by a solid object.
+func (w *World) Passable(pos Position) bool {
+	return w.grid.InBounds(pos) && w.grid.At(pos) != Wall && !w.blocked(pos)
+}
+
+// AddResource places a gatherable resource at pos.
+func (w *World) AddResource(pos Position) {
+	w.resources[pos] = struct{}{}
+}
+
+// ResourceAt reports whether a resource lies at pos.
+func (w *World) ResourceAt(pos Position) bool {
+	_, ok := w.resources[pos]
+	return ok
+}
+
+// AddSpawn registers a spawn point. Spawn points are used in registration
+// order when entities are placed.
+func (w *World) AddSpawn(pos Position) {
+	w.spawns = append(w.spawns, pos)
+}
+
+// Spawns returns a copy of the registered spawn points.
+func (w *World) Spawns() []Position {
+	out := make([]Position, len(w.spawns))
+	copy(out, w.spawns)
+	return out
+}
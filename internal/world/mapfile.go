@@ -0,0 +1,278 @@
+package world
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Hand-authored stage files.
+//
+// A stage file is plain text made of three parts, in order:
+//
+//	# comments start with '#' outside the map section
+//	name: The Atrium
+//	legend:
+//	  A = zone:atrium
+//	  ~ = wall
+//	map:
+//	##########
+//	#S..AA..X#
+//	#...AA..*#
+//	##########
+//
+// Metadata lines are "key: value" pairs; only "name" is recognised. The
+// legend maps single glyphs to a meaning and extends the built-in legend:
+//
+//	#  wall        .  floor      S  spawn (on floor)
+//	X  exit        *  resource   M  marker (at most one)
+//	,  dark floor  !  beacon     o  light (drifts east)
+//
+// Legend meanings are wall, floor, dark, spawn, exit, resource, marker,
+// beacon, light and zone:<name>. All cells sharing a zone glyph form one
+// zone covering their bounding rectangle. Every map row must have the same
+// width. Errors report the file, line and column of the offending character.
+
+// MapError describes a problem in a stage file. Col is 0 when the error
+// concerns a whole line.
+type MapError struct {
+	File string
+	Line int
+	Col  int
+	Msg  string
+}
+
+func (e *MapError) Error() string {
+	if e.Col > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Col, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+type cellKind int
+
+const (
+	cellFloor cellKind = iota
+	cellWall
+	cellDark
+	cellSpawn
+	cellExit
+	cellResource
+	cellMarker
+	cellBeacon
+	cellLight
+	cellZone
+)
+
+type legendEntry struct {
+	kind cellKind
+	zone string
+}
+
+func defaultLegend() map[rune]legendEntry {
+	return map[rune]legendEntry{
+		'#': {kind: cellWall},
+		'.': {kind: cellFloor},
+		'S': {kind: cellSpawn},
+		'X': {kind: cellExit},
+		'*': {kind: cellResource},
+		'M': {kind: cellMarker},
+		',': {kind: cellDark},
+		'!': {kind: cellBeacon},
+		'o': {kind: cellLight},
+	}
+}
+
+func parseLegendMeaning(s string) (legendEntry, bool) {
+	switch s {
+	case "wall":
+		return legendEntry{kind: cellWall}, true
+	case "floor":
+		return legendEntry{kind: cellFloor}, true
+	case "dark":
+		return legendEntry{kind: cellDark}, true
+	case "spawn":
+		return legendEntry{kind: cellSpawn}, true
+	case "exit":
+		return legendEntry{kind: cellExit}, true
+	case "resource":
+		return legendEntry{kind: cellResource}, true
+	case "marker":
+		return legendEntry{kind: cellMarker}, true
+	case "beacon":
+		return legendEntry{kind: cellBeacon}, true
+	case "light":
+		return legendEntry{kind: cellLight}, true
+	}
+	if name, ok := strings.CutPrefix(s, "zone:"); ok && name != "" {
+		return legendEntry{kind: cellZone, zone: name}, true
+	}
+	return legendEntry{}, false
+}
+
+// isComment reports whether a non-map line is a comment. Inside the legend a
+// line such as "# = wall" redefines the wall glyph rather than commenting.
+func isComment(trimmed, section string) bool {
+	if !strings.HasPrefix(trimmed, "#") {
+		return false
+	}
+	if section != "legend" {
+		return true
+	}
+	glyph, _, ok := strings.Cut(trimmed, "=")
+	return !ok || strings.TrimSpace(glyph) != "#"
+}
+
+// LoadMap reads and parses the stage file at path.
+func LoadMap(path string) (*World, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseMap(f, path)
+}
+
+// ParseMap parses a stage file from r. file is used only in error messages.
+func ParseMap(r io.Reader, file string) (*World, error) {
+	legend := defaultLegend()
+	name := ""
+	type row struct {
+		line int
+		text string
+	}
+	rows := []row{}
+	section := "meta"
+
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimRight(sc.Text(), "\r")
+		if section == "map" {
+			if strings.TrimSpace(text) == "" {
+				// Blank lines end the map; anything after them is an error.
+				section = "done"
+				continue
+			}
+			rows = append(rows, row{line: line, text: text})
+			continue
+		}
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || isComment(trimmed, section) {
+			continue
+		}
+		if section == "done" {
+			return nil, &MapError{File: file, Line: line, Col: 1, Msg: "content after end of map"}
+		}
+		switch trimmed {
+		case "legend:":
+			section = "legend"
+			continue
+		case "map:":
+			section = "map"
+			continue
+		}
+		if section == "legend" {
+			glyph, meaning, ok := strings.Cut(trimmed, "=")
+			glyph = strings.TrimSpace(glyph)
+			meaning = strings.TrimSpace(meaning)
+			col := strings.Index(text, trimmed) + 1
+			if !ok || utf8.RuneCountInString(glyph) != 1 {
+				return nil, &MapError{File: file, Line: line, Col: col, Msg: "legend entries must look like \"<glyph> = <meaning>\""}
+			}
+			e, ok := parseLegendMeaning(meaning)
+			if !ok {
+				return nil, &MapError{File: file, Line: line, Col: strings.LastIndex(text, meaning) + 1, Msg: fmt.Sprintf("unknown legend meaning %q", meaning)}
+			}
+			g, _ := utf8.DecodeRuneInString(glyph)
+			legend[g] = e
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, &MapError{File: file, Line: line, Col: 1, Msg: "expected \"key: value\", \"legend:\" or \"map:\""}
+		}
+		switch strings.TrimSpace(key) {
+		case "name":
+			name = strings.TrimSpace(value)
+		default:
+			return nil, &MapError{File: file, Line: line, Col: 1, Msg: fmt.Sprintf("unknown metadata key %q", strings.TrimSpace(key))}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, &MapError{File: file, Line: line + 1, Msg: "missing or empty map section"}
+	}
+
+	width := utf8.RuneCountInString(rows[0].text)
+	height := len(rows)
+	w := New(width, height)
+	w.name = name
+
+	type bounds struct {
+		min, max Position
+	}
+	zones := map[string]*bounds{}
+	zoneOrder := []string{}
+	markerLine := 0
+	for y, r := range rows {
+		if n := utf8.RuneCountInString(r.text); n != width {
+			return nil, &MapError{File: file, Line: r.line, Col: min(n, width) + 1, Msg: fmt.Sprintf("row is %d wide, expected %d", n, width)}
+		}
+		x := 0
+		for _, g := range r.text {
+			pos := Position{X: x, Y: y}
+			e, ok := legend[g]
+			if !ok {
+				return nil, &MapError{File: file, Line: r.line, Col: x + 1, Msg: fmt.Sprintf("glyph %q is not in the legend", g)}
+			}
+			switch e.kind {
+			case cellWall:
+				w.SetTerrain(pos, Wall)
+			case cellDark:
+				w.SetTerrain(pos, Dark)
+			case cellSpawn:
+				w.AddSpawn(pos)
+			case cellExit:
+				w.AddExit(pos)
+			case cellResource:
+				w.AddResource(pos)
+			case cellMarker:
+				if markerLine != 0 {
+					return nil, &MapError{File: file, Line: r.line, Col: x + 1, Msg: fmt.Sprintf("second marker (first on line %d)", markerLine)}
+				}
+				markerLine = r.line
+				w.marker.Position = pos
+			case cellBeacon:
+				w.AddObject(Object{Name: "beacon", Glyph: GlyphBeacon, Position: pos, Behavior: Blink, Period: 3})
+			case cellLight:
+				w.AddObject(Object{Name: "light", Glyph: GlyphLight, Position: pos, Behavior: Drift, Period: 2, Dir: Position{X: 1}})
+			case cellZone:
+				b, ok := zones[e.zone]
+				if !ok {
+					zones[e.zone] = &bounds{min: pos, max: pos}
+					zoneOrder = append(zoneOrder, e.zone)
+					break
+				}
+				b.min.X, b.min.Y = min(b.min.X, pos.X), min(b.min.Y, pos.Y)
+				b.max.X, b.max.Y = max(b.max.X, pos.X), max(b.max.Y, pos.Y)
+			}
+			x++
+		}
+	}
+	for _, z := range zoneOrder {
+		w.AddZone(Zone{Name: z, Min: zones[z].min, Max: zones[z].max})
+	}
+	return w, nil
+}
+
+// Name returns the stage name from its file, or "" for generated stages.
+func (w *World) Name() string {
+	return w.name
+}
@@ -0,0 +1,107 @@
+package world
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const stage = `# test stage
+name: Test Stage
+legend:
+  A = zone:alpha
+  # = wall
+map:
+#####
+#S.X#
+#AA*#
+#AM.#
+#####
+`
+
+func TestParseMap_BuildsWorld(t *testing.T) {
+	w, err := ParseMap(strings.NewReader(stage), "test.map")
+	if err != nil {
+		t.Fatalf("ParseMap: %v", err)
+	}
+	if w.Name() != "Test Stage" || w.Width() != 5 || w.Height() != 5 {
+		t.Fatalf("unexpected stage header: name=%q %dx%d", w.Name(), w.Width(), w.Height())
+	}
+	if w.Terrain(Position{X: 0, Y: 0}) != Wall || w.Terrain(Position{X: 2, Y: 1}) != Floor {
+		t.Fatal("terrain not loaded")
+	}
+	if sp := w.Spawns(); len(sp) != 1 || sp[0] != (Position{X: 1, Y: 1}) {
+		t.Fatalf("spawns = %+v", sp)
+	}
+	if ex := w.Exits(); len(ex) != 1 || ex[0].Position != (Position{X: 3, Y: 1}) || ex[0].Open {
+		t.Fatalf("exits = %+v", ex)
+	}
+	if !w.ResourceAt(Position{X: 3, Y: 2}) {
+		t.Fatal("resource not loaded")
+	}
+	if w.MarkerPosition() != (Position{X: 2, Y: 3}) {
+		t.Fatalf("marker at %+v", w.MarkerPosition())
+	}
+	zones := w.Zones()
+	if len(zones) != 1 || zones[0].Name != "alpha" || zones[0].Min != (Position{X: 1, Y: 2}) || zones[0].Max != (Position{X: 2, Y: 3}) {
+		t.Fatalf("zones = %+v", zones)
+	}
+}
+
+func TestParseMap_ErrorsPointAtLineAndColumn(t *testing.T) {
+	cases := []struct {
+		name      string
+		src       string
+		line, col int
+	}{
+		{"unknown glyph", "map:\n###\n#?#\n###\n", 3, 2},
+		{"ragged row", "map:\n###\n##\n###\n", 3, 3},
+		{"second marker", "map:\nM.M\n", 2, 3},
+		{"bad legend", "legend:\n  Q = lava\nmap:\n.\n", 2, 7},
+		{"unknown key", "title: x\nmap:\n.\n", 1, 1},
+		{"missing map", "name: x\n", 2, 0},
+		{"content after map", "map:\n...\n\nname: y\n", 4, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseMap(strings.NewReader(c.src), "bad.map")
+			var me *MapError
+			if !errors.As(err, &me) {
+				t.Fatalf("expected *MapError, got %v", err)
+			}
+			if me.Line != c.line || me.Col != c.col {
+				t.Fatalf("error at %d:%d, want %d:%d (%v)", me.Line, me.Col, c.line, c.col, err)
+			}
+			if !strings.HasPrefix(err.Error(), "bad.map:") {
+				t.Fatalf("error does not name the file: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadMap_ShippedStage(t *testing.T) {
+	w, err := LoadMap("../../assets/maps/atrium.txt")
+	if err != nil {
+		t.Fatalf("LoadMap: %v", err)
+	}
+	if len(w.Spawns()) == 0 || len(w.Exits()) == 0 || len(w.Zones()) == 0 {
+		t.Fatalf("shipped stage is missing spawns, exits or zones")
+	}
+	if got := len(w.grid.Region(w.Spawns()[0])); got != len(floorCells(w.grid)) {
+		t.Fatalf("shipped stage is not fully connected")
+	}
+}
+
+func TestParseMap_PlacesObjects(t *testing.T) {
+	w, err := ParseMap(strings.NewReader("map:\n.!.\n..o\n"), "objects.map")
+	if err != nil {
+		t.Fatalf("ParseMap: %v", err)
+	}
+	objs := w.Objects()
+	if len(objs) != 2 || objs[0].Behavior != Blink || objs[1].Behavior != Drift {
+		t.Fatalf("objects = %+v", objs)
+	}
+	if w.GlyphAt(Position{X: 2, Y: 1}) != GlyphLight || w.Terrain(Position{X: 1, Y: 0}) != Floor {
+		t.Fatal("object cells should read as objects on floor")
+	}
+}
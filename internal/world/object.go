@@ -0,0 +1,88 @@
+package world
+
+// Behavior selects how a world object changes from tick to tick. The
+// behaviors themselves are rules and live in the game package; World only
+// stores the parameters they need.
+type Behavior uint8
+
+const (
+	// Static objects never change.
+	Static Behavior = iota
+	// Patrol objects walk back and forth along Path, one cell per step.
+	Patrol
+	// Blink objects stay in place and toggle between shown and hidden.
+	Blink
+	// Drift objects move by Dir each step, wrapping at the world edge.
+	Drift
+)
+
+// Glyphs for the stock object kinds.
+const (
+	GlyphSentry rune = 'S'
+	GlyphBeacon rune = '!'
+	GlyphLight  rune = 'o'
+)
+
+// Object is a dynamic world fact other than an entity. Objects are visited
+// in placement order every tick, so their motion depends only on the tick
+// number and the state of the world.
+type Object struct {
+	Name     string
+	Glyph    rune
+	Position Position
+	Behavior Behavior
+
+	// Period is the number of ticks between steps; values below 1 step
+	// every tick.
+	Period int
+
+	// Path holds patrol waypoints and Waypoint the index being walked to.
+	Path     []Position
+	Waypoint int
+
+	// Dir is the per-step offset of a drifting object.
+	Dir Position
+
+	// Hidden objects are not drawn. Blinking objects toggle it.
+	Hidden bool
+
+	// Solid objects block movement like a wall while they are shown.
+	Solid bool
+}
+
+// AddObject places o in the world after any existing objects.
+func (w *World) AddObject(o Object) {
+	w.objects = append(w.objects, o)
+}
+
+// Objects returns a copy of all objects in placement order.
+func (w *World) Objects() []Object {
+	out := make([]Object, len(w.objects))
+	copy(out, w.objects)
+	return out
+}
+
+// SetObject replaces the object at index i. Out-of-range indices are
+// ignored.
+func (w *World) SetObject(i int, o Object) {
+	if i < 0 || i >= len(w.objects) {
+		return
+	}
+	w.objects[i] = o
+}
+
+// ObjectAt returns the first shown object at pos, if any.
+func (w *World) ObjectAt(pos Position) (Object, bool) {
+	for _, o := range w.objects {
+		if o.Position == pos && !o.Hidden {
+			return o, true
+		}
+	}
+	return Object{}, false
+}
+
+// blocked reports whether a shown solid object occupies pos.
+func (w *World) blocked(pos Position) bool {
+	o, ok := w.ObjectAt(pos)
+	return ok && o.Solid
+}
@@ -1 +1,42 @@
 package world
+
+// Glyphs reported to observers. A zero glyph is empty floor; renderers
+// choose how to draw it.
+const (
+	GlyphFloor    rune = 0
+	GlyphMarker   rune = 'M'
+	GlyphExit     rune = 'X'
+	GlyphZone     rune = ':'
+	GlyphWall     rune = '#'
+	GlyphResource rune = '*'
+	GlyphDark     rune = ','
+)
+
+// GlyphAt returns the glyph an observer sees at pos. World facts are layered
+// in a fixed order so the result is deterministic: marker, then shown
+// objects, open exits, resources, walls, control zones, darkness and finally
+// floor. Closed exits are indistinguishable from whatever lies beneath them.
+func (w *World) GlyphAt(pos Position) rune {
+	if w.marker.Position == pos {
+		return GlyphMarker
+	}
+	if o, ok := w.ObjectAt(pos); ok {
+		return o.Glyph
+	}
+	if e, ok := w.ExitAt(pos); ok && e.Open {
+		return GlyphExit
+	}
+	if w.ResourceAt(pos) {
+		return GlyphResource
+	}
+	if w.Terrain(pos) == Wall {
+		return GlyphWall
+	}
+	if w.InZone(pos) {
+		return GlyphZone
+	}
+	if w.Terrain(pos) == Dark {
+		return GlyphDark
+	}
+	return GlyphFloor
+}
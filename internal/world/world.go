@@ -12,17 +12,27 @@ type Position struct {
 }
 
 type World struct {
+	name     string
 	width    int
 	height   int
 	entities map[string]Position
 	marker   Marker
+	exits    []Exit
+	zones    []Zone
+	objects  []Object
+
+	grid      *Grid
+	resources map[Position]struct{}
+	spawns    []Position
 }
 
 func New(width, height int) *World {
 	return &World{
-		width:    width,
-		height:   height,
-		entities: make(map[string]Position),
+		width:     width,
+		height:    height,
+		entities:  make(map[string]Position),
+		grid:      NewGrid(width, height, Floor),
+		resources: make(map[Position]struct{}),
 		marker: Marker{
 			Position: Position{
 				X: width / 2,
@@ -66,3 +76,18 @@ func (w *World) PositionOf(id string) (Position, bool) {
 func (w *World) SetPosition(id string, pos Position) {
 	w.entities[id] = pos
 }
+
+// Remove deletes an entity's position from the world.
+func (w *World) Remove(id string) {
+	delete(w.entities, id)
+}
+
+// Occupied reports whether any entity currently stands at pos.
+func (w *World) Occupied(pos Position) bool {
+	for _, p := range w.entities {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
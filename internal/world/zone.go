@@ -0,0 +1,59 @@
+package world
+
+// Zone is a named rectangular region of the grid. Min and Max are inclusive
+// corners. Zones carry no state of their own; who holds a zone is decided by
+// the game rules from entity positions.
+type Zone struct {
+	Name string
+	Min  Position
+	Max  Position
+}
+
+// Contains reports whether pos lies inside the zone.
+func (z Zone) Contains(pos Position) bool {
+	return pos.X >= z.Min.X && pos.X <= z.Max.X && pos.Y >= z.Min.Y && pos.Y <= z.Max.Y
+}
+
+// Center returns the cell nearest the middle of the zone.
+func (z Zone) Center() Position {
+	return Position{X: (z.Min.X + z.Max.X) / 2, Y: (z.Min.Y + z.Max.Y) / 2}
+}
+
+// AddZone registers a zone. Zones with duplicate names replace the earlier
+// definition so maps can redefine a zone without leaving stale copies.
+func (w *World) AddZone(z Zone) {
+	for i, existing := range w.zones {
+		if existing.Name == z.Name {
+			w.zones[i] = z
+			return
+		}
+	}
+	w.zones = append(w.zones, z)
+}
+
+// Zones returns a copy of all zones in registration order.
+func (w *World) Zones() []Zone {
+	out := make([]Zone, len(w.zones))
+	copy(out, w.zones)
+	return out
+}
+
+// InZone reports whether pos lies inside any zone.
+func (w *World) InZone(pos Position) bool {
+	for _, z := range w.zones {
+		if z.Contains(pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// EntityIDs returns the IDs of all positioned entities. Order is unspecified;
+// callers that need determinism must sort.
+func (w *World) EntityIDs() []string {
+	ids := make([]string, 0, len(w.entities))
+	for id := range w.entities {
+		ids = append(ids, id)
+	}
+	return ids
+}